@@ -5,6 +5,7 @@ set REPO=f-asai-monox/mcpify
 set INSTALL_DIR=%LOCALAPPDATA%\mcpify
 set BINARY_NAME=mcp-server-stdio.exe
 set HTTP_BINARY_NAME=mcp-server-http.exe
+set MCPIFY_BINARY_NAME=mcpify.exe
 
 echo Installing mcpify...
 
@@ -68,6 +69,9 @@ if exist "%TEMP%\mcpify_extract\%BINARY_NAME%" (
 if exist "%TEMP%\mcpify_extract\%HTTP_BINARY_NAME%" (
     move /Y "%TEMP%\mcpify_extract\%HTTP_BINARY_NAME%" "%INSTALL_DIR%\" >nul
 )
+if exist "%TEMP%\mcpify_extract\%MCPIFY_BINARY_NAME%" (
+    move /Y "%TEMP%\mcpify_extract\%MCPIFY_BINARY_NAME%" "%INSTALL_DIR%\" >nul
+)
 
 :: Clean up
 if exist "%TEMP%\%FILENAME%" del "%TEMP%\%FILENAME%"
@@ -87,7 +91,7 @@ echo.
 echo Successfully installed mcpify to %INSTALL_DIR%
 echo.
 echo To get started, restart your terminal and run:
-echo   mcp-server-stdio --help
+echo   mcpify help
 echo.
 
 endlocal
\ No newline at end of file
@@ -6,6 +6,7 @@ $REPO = "f-asai-monox/mcpify"
 $INSTALL_DIR = "$env:LOCALAPPDATA\mcpify"
 $BINARY_NAME = "mcp-server-stdio.exe"
 $HTTP_BINARY_NAME = "mcp-server-http.exe"
+$MCPIFY_BINARY_NAME = "mcpify.exe"
 
 Write-Host "Installing mcpify..." -ForegroundColor Green
 
@@ -71,6 +72,9 @@ if (Test-Path "$extractPath\$BINARY_NAME") {
 if (Test-Path "$extractPath\$HTTP_BINARY_NAME") {
     Move-Item -Path "$extractPath\$HTTP_BINARY_NAME" -Destination "$INSTALL_DIR\" -Force
 }
+if (Test-Path "$extractPath\$MCPIFY_BINARY_NAME") {
+    Move-Item -Path "$extractPath\$MCPIFY_BINARY_NAME" -Destination "$INSTALL_DIR\" -Force
+}
 
 # Clean up
 Remove-Item $tempFile -Force -ErrorAction SilentlyContinue
@@ -90,5 +94,5 @@ if ($currentPath -notlike "*$INSTALL_DIR*") {
 
 Write-Host "`nSuccessfully installed mcpify to $INSTALL_DIR" -ForegroundColor Green
 Write-Host "`nTo get started, restart your terminal and run:" -ForegroundColor Cyan
-Write-Host "  mcp-server-stdio --help" -ForegroundColor White
+Write-Host "  mcpify help" -ForegroundColor White
 Write-Host ""
\ No newline at end of file
@@ -6,6 +6,7 @@ REPO="f-asai-monox/mcpify"
 INSTALL_DIR="${INSTALL_DIR:-/usr/local/bin}"
 STDIO_BINARY="mcp-server-stdio"
 HTTP_BINARY="mcp-server-http"
+MCPIFY_BINARY="mcpify"
 
 detect_os() {
     OS=""
@@ -60,6 +61,17 @@ download_and_install() {
     
     echo "Installing to ${INSTALL_DIR}..."
     
+    # Install mcpify binary
+    if [ -f "${temp_dir}/${MCPIFY_BINARY}" ]; then
+        if [ -w "$INSTALL_DIR" ]; then
+            mv "${temp_dir}/${MCPIFY_BINARY}" "$INSTALL_DIR/"
+        else
+            sudo mv "${temp_dir}/${MCPIFY_BINARY}" "$INSTALL_DIR/"
+        fi
+        chmod +x "${INSTALL_DIR}/${MCPIFY_BINARY}"
+        echo "Successfully installed ${MCPIFY_BINARY} to ${INSTALL_DIR}/${MCPIFY_BINARY}"
+    fi
+
     # Install stdio binary
     if [ -f "${temp_dir}/${STDIO_BINARY}" ]; then
         if [ -w "$INSTALL_DIR" ]; then
@@ -108,10 +120,11 @@ main() {
     echo "Installation complete!"
     echo ""
     echo "Available commands:"
+    echo "  mcpify            - serve, validate, list-tools, call, import and mock"
     echo "  mcp-server-stdio  - MCP server with stdio transport"
     echo "  mcp-server-http   - MCP server with HTTP transport"
     echo ""
-    echo "Run 'mcp-server-stdio --help' or 'mcp-server-http --help' to get started."
+    echo "Run 'mcpify help' to get started."
     
     if ! command -v "$STDIO_BINARY" &> /dev/null; then
         echo ""
@@ -0,0 +1,194 @@
+package bridge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"mcp-bridge/internal/config"
+)
+
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+func (s breakerState) String() string {
+	switch s {
+	case breakerOpen:
+		return "open"
+	case breakerHalfOpen:
+		return "half-open"
+	}
+	return "closed"
+}
+
+// circuitBreaker tracks the health of one API, see config.CircuitBreakerConfig
+type circuitBreaker struct {
+	api string
+	cfg config.CircuitBreakerConfig
+
+	mu          sync.Mutex
+	state       breakerState
+	failures    int // consecutive failed calls
+	lastFailure string
+	openUntil   time.Time
+	probes      int // probes in flight while half-open
+	successes   int // successful probes while half-open
+}
+
+// CircuitOpenError is returned for calls rejected by an open circuit breaker
+type CircuitOpenError struct {
+	API         string
+	Failures    int
+	LastFailure string
+	// RetryAt is when the breaker lets probe calls through; zero while the
+	// probes are in flight
+	RetryAt time.Time
+}
+
+func (e *CircuitOpenError) Error() string {
+	msg := fmt.Sprintf("API %s is unavailable: the circuit breaker opened after %d consecutive failures (last: %s)",
+		e.API, e.Failures, e.LastFailure)
+	if e.RetryAt.IsZero() {
+		return msg + "; calls are rejected until the API recovers"
+	}
+	wait := time.Until(e.RetryAt).Round(time.Second)
+	return fmt.Sprintf("%s; calls are rejected for another %s", msg, max(wait, time.Second))
+}
+
+// BreakerStatus is the state of an API's circuit breaker as shown in the
+// rest-api://status resource
+type BreakerStatus struct {
+	State               string     `json:"state"`
+	ConsecutiveFailures int        `json:"consecutiveFailures"`
+	LastFailure         string     `json:"lastFailure,omitempty"`
+	OpenUntil           *time.Time `json:"openUntil,omitempty"`
+}
+
+// breakerFor returns the circuit breaker for the endpoint's API, creating it
+// on first use, or nil if the API has none
+func (c *RestClient) breakerFor(endpoint APIEndpoint) *circuitBreaker {
+	if endpoint.CircuitBreaker == nil {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if breaker, ok := c.breakers[endpoint.APIName]; ok {
+		return breaker
+	}
+
+	breaker := &circuitBreaker{api: endpoint.APIName, cfg: *endpoint.CircuitBreaker}
+	c.breakers[endpoint.APIName] = breaker
+	return breaker
+}
+
+// allow reports whether a call may be sent, and whether it is a probe of a
+// half-open breaker. A rejected call gets a *CircuitOpenError.
+func (cb *circuitBreaker) allow() (bool, error) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	switch cb.state {
+	case breakerOpen:
+		if time.Now().Before(cb.openUntil) {
+			return false, cb.openError(cb.openUntil)
+		}
+		cb.state = breakerHalfOpen
+		cb.probes = 0
+		cb.successes = 0
+		fallthrough
+	case breakerHalfOpen:
+		if cb.probes+cb.successes >= cb.cfg.HalfOpenProbes {
+			return false, cb.openError(time.Time{})
+		}
+		cb.probes++
+		return true, nil
+	}
+	return false, nil
+}
+
+func (cb *circuitBreaker) openError(retryAt time.Time) *CircuitOpenError {
+	return &CircuitOpenError{API: cb.api, Failures: cb.failures, LastFailure: cb.lastFailure, RetryAt: retryAt}
+}
+
+// done records the outcome of a call that allow let through. Connection
+// errors, timeouts and 5xx statuses are failures; any other response shows
+// that the API is up. Calls cancelled by the client do not count.
+func (cb *circuitBreaker) done(ctx context.Context, probe bool, resp *http.Response, err error) {
+	cancelled := err != nil && errors.Is(ctx.Err(), context.Canceled)
+	failure := ""
+	if err != nil {
+		failure = err.Error()
+	} else if resp.StatusCode >= 500 {
+		failure = fmt.Sprintf("HTTP %d", resp.StatusCode)
+	}
+
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	switch {
+	case cb.state == breakerClosed && !probe:
+	case cb.state == breakerHalfOpen && probe:
+		if cb.probes > 0 {
+			cb.probes--
+		}
+	default:
+		// The breaker changed state while the call was in flight, so its
+		// outcome says nothing about the API now
+		return
+	}
+	if cancelled {
+		return
+	}
+
+	if failure == "" {
+		if cb.state == breakerHalfOpen {
+			cb.successes++
+			if cb.successes < cb.cfg.HalfOpenProbes {
+				return
+			}
+			cb.state = breakerClosed
+			cb.lastFailure = ""
+		}
+		cb.failures = 0
+		return
+	}
+
+	cb.failures++
+	cb.lastFailure = failure
+	if cb.state == breakerHalfOpen || cb.failures >= cb.cfg.FailureThreshold {
+		cb.state = breakerOpen
+		cb.openUntil = time.Now().Add(time.Duration(cb.cfg.OpenSeconds) * time.Second)
+	}
+}
+
+// status returns the state of the breaker
+func (cb *circuitBreaker) status() BreakerStatus {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	status := BreakerStatus{
+		State:               cb.state.String(),
+		ConsecutiveFailures: cb.failures,
+		LastFailure:         cb.lastFailure,
+	}
+	if cb.state == breakerOpen {
+		if time.Now().Before(cb.openUntil) {
+			openUntil := cb.openUntil
+			status.OpenUntil = &openUntil
+		} else {
+			// The next call is a probe
+			status.State = breakerHalfOpen.String()
+		}
+	}
+	return status
+}
@@ -0,0 +1,90 @@
+package bridge
+
+import "mcp-bridge/internal/config"
+
+// EndpointsFromConfig converts the endpoints of every configured API into
+// bridge endpoints. Tool names are prefixed with the API name, and
+// endpoint-level headers override API-level headers. Endpoints whose method
+// is not allowed are left out. An API's dry-run setting and allowed methods
+// override the server's, as do the limits it sets.
+func EndpointsFromConfig(cfg *config.Config) []APIEndpoint {
+	var endpoints []APIEndpoint
+
+	for _, api := range cfg.APIs {
+		dryRun := api.DryRun
+		if dryRun == nil {
+			dryRun = cfg.Server.DryRun
+		}
+		allowedMethods := api.AllowedMethods
+		if len(allowedMethods) == 0 {
+			allowedMethods = cfg.Server.AllowedMethods
+		}
+		var limits config.LimitsConfig
+		for _, l := range []*config.LimitsConfig{cfg.Server.Limits, api.Limits} {
+			if l == nil {
+				continue
+			}
+			if l.MaxResponseBytes > 0 {
+				limits.MaxResponseBytes = l.MaxResponseBytes
+			}
+			if l.MaxOutputBytes > 0 {
+				limits.MaxOutputBytes = l.MaxOutputBytes
+			}
+		}
+
+		for _, endpoint := range api.Endpoints {
+			if !config.MethodAllowed(allowedMethods, endpoint.Method) {
+				continue
+			}
+
+			// Merge API-level headers with endpoint-level headers
+			mergedHeaders := make(map[string]string)
+			// Add API-level headers first
+			for key, value := range api.Headers {
+				mergedHeaders[key] = value
+			}
+			// Override with endpoint-level headers
+			for key, value := range endpoint.Headers {
+				mergedHeaders[key] = value
+			}
+
+			apiEndpoint := APIEndpoint{
+				Name:           api.Name + "__" + endpoint.Name,
+				Description:    endpoint.Description,
+				Method:         endpoint.Method,
+				Path:           endpoint.Path,
+				Headers:        mergedHeaders,
+				Parameters:     make([]APIParameter, len(endpoint.Parameters)),
+				APIName:        api.Name,
+				BaseURL:        api.BaseURL,
+				Auth:           api.Auth,
+				Timeout:        api.Timeout,
+				Client:         api.Client,
+				DryRun:         dryRun.Applies(endpoint.Method),
+				Annotations:    endpoint.Annotations,
+				OutputSchema:   endpoint.OutputSchema,
+				Response:       endpoint.Response,
+				Limits:         limits,
+				Pagination:     endpoint.Pagination,
+				Retry:          api.Retry,
+				CircuitBreaker: api.CircuitBreaker,
+			}
+
+			for i, param := range endpoint.Parameters {
+				apiEndpoint.Parameters[i] = APIParameter{
+					Name:        param.Name,
+					Type:        param.Type,
+					Required:    param.Required,
+					Description: param.Description,
+					Default:     param.Default,
+					In:          param.In,
+					Field:       param.Field,
+				}
+			}
+
+			endpoints = append(endpoints, apiEndpoint)
+		}
+	}
+
+	return endpoints
+}
@@ -1,10 +1,16 @@
 package bridge
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strconv"
+	"strings"
+	"sync"
+	"time"
 
+	"mcp-bridge/internal/config"
 	"mcp-bridge/internal/mcp"
 	"mcp-bridge/internal/transport"
 	"mcp-bridge/pkg/types"
@@ -14,6 +20,8 @@ type MCPBridge struct {
 	server     *mcp.Server
 	restClient *RestClient
 	endpoints  []APIEndpoint
+	masker     *config.Masker
+	mu         sync.RWMutex // guards endpoints and masker, which requests read concurrently
 }
 
 func NewMCPBridge(transport transport.Transport) *MCPBridge {
@@ -30,6 +38,21 @@ func NewMCPBridge(transport transport.Transport) *MCPBridge {
 	return bridge
 }
 
+// NewFromConfig returns a bridge serving cfg over transport, which must
+// already be validated. This is how every command builds its bridge, so that
+// the tools a command lists or calls are the ones the server exposes. The
+// transport may be nil for bridges that are only used through CallTool.
+func NewFromConfig(transport transport.Transport, cfg *config.Config) (*MCPBridge, error) {
+	bridge := NewMCPBridge(transport)
+	bridge.SetMaxConcurrency(cfg.Server.MaxConcurrency)
+	bridge.SetRequestTimeout(time.Duration(cfg.Server.RequestTimeout) * time.Second)
+
+	if err := bridge.ApplyConfig(cfg); err != nil {
+		return nil, err
+	}
+	return bridge, nil
+}
+
 func (b *MCPBridge) setupMCPServer() {
 	for _, endpoint := range b.endpoints {
 		tool := b.createToolFromEndpoint(endpoint)
@@ -46,6 +69,13 @@ func (b *MCPBridge) setupMCPServer() {
 		MimeType:    "application/json",
 	}
 	b.server.AddResource(apiDocsResource)
+
+	b.server.AddResource(types.Resource{
+		URI:         "rest-api://status",
+		Name:        "REST API Status",
+		Description: "Circuit breaker state of each REST API",
+		MimeType:    "application/json",
+	})
 }
 
 func (b *MCPBridge) createToolFromEndpoint(endpoint APIEndpoint) types.Tool {
@@ -75,36 +105,130 @@ func (b *MCPBridge) createToolFromEndpoint(endpoint APIEndpoint) types.Tool {
 		}
 	}
 
+	if endpoint.Pagination != nil && endpoint.Pagination.Mode == "cursor" {
+		properties[config.CursorArgument] = map[string]interface{}{
+			"type":        "string",
+			"description": "Cursor of the page to fetch, as returned by the previous call. Omit for the first page",
+		}
+	}
+
 	schema["required"] = required
 
-	return types.Tool{
+	description := fmt.Sprintf("%s (%s %s)", endpoint.Description, endpoint.Method, endpoint.Path)
+	if endpoint.DryRun {
+		description += " [dry run: the request is returned, not sent]"
+	}
+
+	tool := types.Tool{
 		Name:        endpoint.Name,
-		Description: fmt.Sprintf("%s (%s %s)", endpoint.Description, endpoint.Method, endpoint.Path),
+		Description: description,
 		InputSchema: schema,
+		Annotations: toolAnnotations(endpoint),
+	}
+	if schema := responseSchema(endpoint); schema != nil {
+		tool.OutputSchema = outputSchema(schema)
+	}
+	return tool
+}
+
+// responseSchema returns the schema of the data a call of the endpoint
+// returns: the items of all pages in aggregate mode, the configured output
+// schema otherwise
+func responseSchema(endpoint APIEndpoint) map[string]interface{} {
+	if endpoint.OutputSchema != nil && endpoint.Pagination != nil && endpoint.Pagination.Mode != "cursor" {
+		return aggregateSchema(endpoint.Pagination, endpoint.OutputSchema)
+	}
+	return endpoint.OutputSchema
+}
+
+// outputSchema returns the output schema of a tool. MCP requires structured
+// content to be an object, so other responses are wrapped in an object with
+// a single "result" property.
+func outputSchema(schema map[string]interface{}) map[string]interface{} {
+	if schema["type"] == "object" {
+		return schema
+	}
+	return map[string]interface{}{
+		"type":       "object",
+		"properties": map[string]interface{}{"result": schema},
+		"required":   []string{"result"},
+	}
+}
+
+// structuredContent returns the parsed JSON response as structured content,
+// wrapped like the output schema of the endpoint's tool
+func structuredContent(endpoint APIEndpoint, data interface{}) map[string]interface{} {
+	if object, ok := data.(map[string]interface{}); ok {
+		if schema := responseSchema(endpoint); schema == nil || schema["type"] == "object" {
+			return object
+		}
 	}
+	return map[string]interface{}{"result": data}
+}
+
+// toolAnnotations derives the annotations of an endpoint's tool from its
+// HTTP method: safe methods are read-only, PUT and DELETE are idempotent and
+// PUT, PATCH and DELETE, which overwrite or remove existing data, are
+// destructive. Whether a POST is destructive depends on the API, so it gets
+// no hint. A tool in dry-run mode changes nothing upstream, so
+// it is read-only too. Every tool calls an external API and is therefore
+// open-world. Configured annotations override the derived ones.
+func toolAnnotations(endpoint APIEndpoint) *types.ToolAnnotations {
+	method := strings.ToUpper(endpoint.Method)
+	readOnly := config.IsSafeMethod(method) || endpoint.DryRun
+	annotations := &types.ToolAnnotations{
+		ReadOnlyHint:  boolPtr(readOnly),
+		OpenWorldHint: boolPtr(true),
+	}
+	if !readOnly {
+		if method != "POST" {
+			annotations.DestructiveHint = boolPtr(method == "PUT" || method == "PATCH" || method == "DELETE")
+		}
+		annotations.IdempotentHint = boolPtr(method == "PUT" || method == "DELETE")
+	}
+
+	if override := endpoint.Annotations; override != nil {
+		annotations.Title = override.Title
+		if override.ReadOnlyHint != nil {
+			annotations.ReadOnlyHint = override.ReadOnlyHint
+		}
+		if override.DestructiveHint != nil {
+			annotations.DestructiveHint = override.DestructiveHint
+		}
+		if override.IdempotentHint != nil {
+			annotations.IdempotentHint = override.IdempotentHint
+		}
+		if override.OpenWorldHint != nil {
+			annotations.OpenWorldHint = override.OpenWorldHint
+		}
+	}
+
+	return annotations
+}
+
+func boolPtr(b bool) *bool {
+	return &b
 }
 
 func (b *MCPBridge) convertParamType(paramType string) string {
 	switch paramType {
 	case "integer", "int":
 		return "number"
-	case "float", "double":
+	case "float", "double", "number":
 		return "number"
 	case "bool", "boolean":
 		return "boolean"
+	case "array":
+		return "array"
+	case "object":
+		return "object"
 	default:
 		return "string"
 	}
 }
 
-func (b *MCPBridge) handleToolCall(name string, args map[string]interface{}) (*types.CallToolResult, error) {
-	var endpoint *APIEndpoint
-	for _, ep := range b.endpoints {
-		if ep.Name == name {
-			endpoint = &ep
-			break
-		}
-	}
+func (b *MCPBridge) handleToolCall(ctx context.Context, name string, args map[string]interface{}) (*types.CallToolResult, error) {
+	endpoint := b.findEndpoint(name)
 
 	if endpoint == nil {
 		return &types.CallToolResult{
@@ -120,20 +244,67 @@ func (b *MCPBridge) handleToolCall(name string, args map[string]interface{}) (*t
 
 	processedArgs := b.processArguments(args, endpoint.Parameters)
 
-	response, err := b.restClient.MakeRequest(*endpoint, processedArgs)
+	var response *APIResponse
+	var err error
+	if endpoint.Pagination != nil {
+		response, err = b.fetchPages(ctx, *endpoint, processedArgs)
+	} else {
+		response, err = b.restClient.MakeRequestWithContext(ctx, *endpoint, processedArgs)
+	}
 	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return &types.CallToolResult{
+				Content: []types.ToolResult{
+					{
+						Type: "text",
+						Text: b.mask(fmt.Sprintf("Tool call timed out: %v", err)),
+					},
+				},
+				IsError: true,
+			}, nil
+		}
 		return &types.CallToolResult{
 			Content: []types.ToolResult{
 				{
 					Type: "text",
-					Text: fmt.Sprintf("Error calling API: %v", err),
+					Text: b.mask(fmt.Sprintf("Error calling API: %v", err)),
 				},
 			},
 			IsError: true,
 		}, nil
 	}
 
-	return b.formatAPIResponse(response), nil
+	if endpoint.Response != nil && response.Data != nil {
+		if err := transformResponse(endpoint.Response, response); err != nil {
+			return &types.CallToolResult{
+				Content: []types.ToolResult{
+					{
+						Type: "text",
+						Text: b.mask(fmt.Sprintf("Error transforming response: %v", err)),
+					},
+				},
+				IsError: true,
+			}, nil
+		}
+	}
+
+	if endpoint.Limits.MaxOutputBytes > 0 {
+		truncateResponse(endpoint.Limits.MaxOutputBytes, response)
+	}
+
+	return b.formatAPIResponse(*endpoint, response), nil
+}
+
+func (b *MCPBridge) findEndpoint(name string) *APIEndpoint {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for _, ep := range b.endpoints {
+		if ep.Name == name {
+			return &ep
+		}
+	}
+	return nil
 }
 
 func (b *MCPBridge) processArguments(args map[string]interface{}, params []APIParameter) map[string]interface{} {
@@ -159,7 +330,7 @@ func (b *MCPBridge) processArguments(args map[string]interface{}, params []APIPa
 			} else {
 				processed[key] = value
 			}
-		case "float", "double":
+		case "float", "double", "number":
 			if str, ok := value.(string); ok {
 				if floatVal, err := strconv.ParseFloat(str, 64); err == nil {
 					processed[key] = floatVal
@@ -187,67 +358,97 @@ func (b *MCPBridge) processArguments(args map[string]interface{}, params []APIPa
 	return processed
 }
 
-func (b *MCPBridge) formatAPIResponse(response *APIResponse) *types.CallToolResult {
+// formatAPIResponse renders the response as text. A JSON response is also
+// returned as structured content.
+func (b *MCPBridge) formatAPIResponse(endpoint APIEndpoint, response *APIResponse) *types.CallToolResult {
+	if response.Request != nil {
+		request := b.mask(response.Request.Format(nil))
+		return &types.CallToolResult{
+			Content: []types.ToolResult{
+				{
+					Type: "text",
+					Text: fmt.Sprintf("Dry run: the request was not sent.\n\nRequest:\n%s", request),
+				},
+			},
+			IsError: false,
+		}
+	}
+
 	if response.Error != "" {
+		text := fmt.Sprintf("API Error: %s", response.Error)
+		if response.Attempts > 1 {
+			text += fmt.Sprintf(" (after %d attempts)", response.Attempts)
+		}
 		return &types.CallToolResult{
 			Content: []types.ToolResult{
 				{
 					Type: "text",
-					Text: fmt.Sprintf("API Error: %s", response.Error),
+					Text: text,
 				},
 			},
 			IsError: true,
+			Meta:    resultMeta(endpoint, response),
 		}
 	}
 
-	var resultText string
+	body := response.Body
+	var structured map[string]interface{}
 	if response.Data != nil {
 		if jsonData, err := json.MarshalIndent(response.Data, "", "  "); err == nil {
-			resultText = fmt.Sprintf("Status: %d\n\nResponse:\n%s", response.StatusCode, string(jsonData))
-		} else {
-			resultText = fmt.Sprintf("Status: %d\n\nResponse:\n%s", response.StatusCode, response.Body)
+			body = string(jsonData)
+			structured = structuredContent(endpoint, response.Data)
 		}
-	} else {
-		resultText = fmt.Sprintf("Status: %d\n\nResponse:\n%s", response.StatusCode, response.Body)
 	}
 
+	var resultText strings.Builder
+	fmt.Fprintf(&resultText, "Status: %d\n", response.StatusCode)
+	if response.Attempts > 1 {
+		fmt.Fprintf(&resultText, "Note: succeeded after %d attempts\n", response.Attempts)
+	}
+	for _, note := range response.Notes {
+		fmt.Fprintf(&resultText, "Note: %s\n", note)
+	}
+	fmt.Fprintf(&resultText, "\nResponse:\n%s", body)
+
 	return &types.CallToolResult{
 		Content: []types.ToolResult{
 			{
 				Type: "text",
-				Text: resultText,
+				Text: resultText.String(),
 			},
 		},
-		IsError: false,
+		StructuredContent: structured,
+		IsError:           false,
+		Meta:              resultMeta(endpoint, response),
+	}
+}
+
+// resultMeta returns the metadata of a tool result: the number of attempts
+// for endpoints with a retry policy
+func resultMeta(endpoint APIEndpoint, response *APIResponse) map[string]interface{} {
+	if endpoint.Retry == nil || response.Attempts == 0 {
+		return nil
 	}
+	return map[string]interface{}{"attempts": response.Attempts}
 }
 
-func (b *MCPBridge) handleResourceRead(uri string) (*types.ReadResourceResult, error) {
+func (b *MCPBridge) handleResourceRead(_ context.Context, uri string) (*types.ReadResourceResult, error) {
 	switch uri {
 	case "rest-api://docs":
 		apisByName := make(map[string][]APIEndpoint)
+		b.mu.RLock()
 		for _, endpoint := range b.endpoints {
 			apisByName[endpoint.APIName] = append(apisByName[endpoint.APIName], endpoint)
 		}
+		b.mu.RUnlock()
 
-		docsData := map[string]interface{}{
+		return b.jsonResource(uri, map[string]interface{}{
 			"apis": apisByName,
-		}
-
-		jsonData, err := json.MarshalIndent(docsData, "", "  ")
-		if err != nil {
-			return nil, fmt.Errorf("error marshaling docs: %w", err)
-		}
-
-		return &types.ReadResourceResult{
-			Contents: []types.ResourceContent{
-				{
-					URI:      uri,
-					MimeType: "application/json",
-					Text:     string(jsonData),
-				},
-			},
-		}, nil
+		})
+	case "rest-api://status":
+		return b.jsonResource(uri, map[string]interface{}{
+			"apis": b.apiStatus(),
+		})
 	default:
 		return &types.ReadResourceResult{
 			Contents: []types.ResourceContent{
@@ -261,16 +462,134 @@ func (b *MCPBridge) handleResourceRead(uri string) (*types.ReadResourceResult, e
 	}
 }
 
+// jsonResource returns data as the JSON contents of the resource at uri
+func (b *MCPBridge) jsonResource(uri string, data interface{}) (*types.ReadResourceResult, error) {
+	jsonData, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling %s: %w", uri, err)
+	}
+
+	return &types.ReadResourceResult{
+		Contents: []types.ResourceContent{
+			{
+				URI:      uri,
+				MimeType: "application/json",
+				Text:     b.mask(string(jsonData)),
+			},
+		},
+	}, nil
+}
+
+// APIStatus is the state of an API as shown in the rest-api://status
+// resource
+type APIStatus struct {
+	Endpoints      int            `json:"endpoints"`
+	CircuitBreaker *BreakerStatus `json:"circuitBreaker,omitempty"`
+}
+
+func (b *MCPBridge) apiStatus() map[string]*APIStatus {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	apis := make(map[string]*APIStatus)
+	for _, endpoint := range b.endpoints {
+		status, ok := apis[endpoint.APIName]
+		if !ok {
+			status = &APIStatus{}
+			if breaker := b.restClient.breakerFor(endpoint); breaker != nil {
+				breakerStatus := breaker.status()
+				status.CircuitBreaker = &breakerStatus
+			}
+			apis[endpoint.APIName] = status
+		}
+		status.Endpoints++
+	}
+	return apis
+}
+
 func (b *MCPBridge) Start() error {
 	return b.server.Start()
 }
 
+// Tools returns the tools the bridge exposes, in the order they are listed
+// to clients
+func (b *MCPBridge) Tools() []types.Tool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	tools := make([]types.Tool, 0, len(b.endpoints))
+	for _, endpoint := range b.endpoints {
+		tools = append(tools, b.createToolFromEndpoint(endpoint))
+	}
+	return tools
+}
+
+// CallTool invokes a tool directly, as a tools/call request would
+func (b *MCPBridge) CallTool(ctx context.Context, name string, args map[string]interface{}) (*types.CallToolResult, error) {
+	return b.handleToolCall(ctx, name, args)
+}
+
 func (b *MCPBridge) SetAPIHeader(key, value string) {
 	b.restClient.SetHeader(key, value)
 }
 
+// SetMasker sets the masker applied to the docs resource and to error
+// messages, so that configured secrets are not exposed to clients.
+func (b *MCPBridge) SetMasker(masker *config.Masker) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.masker = masker
+}
+
+func (b *MCPBridge) mask(s string) string {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.masker.Mask(s)
+}
+
+// ApplyConfig replaces the endpoints, default headers and secret masking
+// with those of cfg, which must already be validated. The endpoint set and
+// the tool list are swapped together, and connected clients are sent
+// notifications/tools/list_changed. Server settings such as maxConcurrency
+// are only read at startup. The configuration is applied even when the
+// notification fails; the returned error only reports that failure.
+func (b *MCPBridge) ApplyConfig(cfg *config.Config) error {
+	endpoints := EndpointsFromConfig(cfg)
+	tools := make([]types.Tool, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		tools = append(tools, b.createToolFromEndpoint(endpoint))
+	}
+
+	headers := map[string]string{"Content-Type": "application/json"}
+	for key, value := range cfg.Headers {
+		headers[key] = value
+	}
+
+	b.mu.Lock()
+	b.endpoints = endpoints
+	b.masker = config.NewMasker(cfg.Secrets())
+	b.restClient.Reset(headers, endpoints)
+	b.server.SetTools(tools)
+	b.mu.Unlock()
+
+	if err := b.server.NotifyToolsListChanged(); err != nil {
+		return fmt.Errorf("configuration applied, but notifying clients of the tool list change failed: %w", err)
+	}
+	return nil
+}
+
+func (b *MCPBridge) SetMaxConcurrency(n int) {
+	b.server.SetMaxConcurrency(n)
+}
+
+func (b *MCPBridge) SetRequestTimeout(timeout time.Duration) {
+	b.server.SetRequestTimeout(timeout)
+}
+
 func (b *MCPBridge) AddCustomEndpoint(endpoint APIEndpoint) {
+	b.mu.Lock()
 	b.endpoints = append(b.endpoints, endpoint)
+	b.mu.Unlock()
 	tool := b.createToolFromEndpoint(endpoint)
 	b.server.AddTool(tool)
 }
@@ -0,0 +1,164 @@
+package bridge
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"mcp-bridge/internal/config"
+)
+
+// tokenRefreshWindow is how long before expiry a cached token is replaced,
+// so that requests do not race the token's expiry on the upstream side.
+const tokenRefreshWindow = 30 * time.Second
+
+// tokenSource fetches and caches OAuth2 client credentials tokens for one API
+type tokenSource struct {
+	cfg    config.OAuth2AuthConfig
+	client *http.Client
+
+	mu        sync.Mutex
+	token     string
+	refreshAt time.Time // zero when the token does not expire
+}
+
+type tokenResponse struct {
+	AccessToken      string      `json:"access_token"`
+	TokenType        string      `json:"token_type"`
+	ExpiresIn        json.Number `json:"expires_in"`
+	Error            string      `json:"error"`
+	ErrorDescription string      `json:"error_description"`
+}
+
+// tokenSourceFor returns the token source for the endpoint's API and OAuth2
+// settings, creating it on first use so the token cache is shared by all of
+// the API's endpoints.
+func (c *RestClient) tokenSourceFor(endpoint APIEndpoint, cfg *config.OAuth2AuthConfig) *tokenSource {
+	client := c.httpClientFor(endpoint)
+
+	key := endpoint.APIName + "\x00" + cfg.TokenURL + "\x00" + cfg.ClientID
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if source, ok := c.tokens[key]; ok {
+		return source
+	}
+
+	source := &tokenSource{cfg: *cfg, client: client}
+	c.tokens[key] = source
+	return source
+}
+
+// Token returns the cached access token, fetching a new one when there is
+// none or the cached one is about to expire.
+func (s *tokenSource) Token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token != "" && (s.refreshAt.IsZero() || time.Now().Before(s.refreshAt)) {
+		return s.token, nil
+	}
+
+	token, lifetime, err := s.fetch(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	s.token = token
+	s.refreshAt = time.Time{}
+	if lifetime > 0 {
+		window := tokenRefreshWindow
+		if window > lifetime/2 {
+			window = lifetime / 2
+		}
+		s.refreshAt = time.Now().Add(lifetime - window)
+	}
+
+	return s.token, nil
+}
+
+// Invalidate drops the cached token if it is still the given one, so that
+// the next call to Token fetches a fresh token.
+func (s *tokenSource) Invalidate(token string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.token == token {
+		s.token = ""
+	}
+}
+
+func (s *tokenSource) fetch(ctx context.Context) (string, time.Duration, error) {
+	form := url.Values{}
+	form.Set("grant_type", "client_credentials")
+	if len(s.cfg.Scopes) > 0 {
+		form.Set("scope", strings.Join(s.cfg.Scopes, " "))
+	}
+	if s.cfg.Audience != "" {
+		form.Set("audience", s.cfg.Audience)
+	}
+	for key, value := range s.cfg.Params {
+		form.Set(key, value)
+	}
+	if s.cfg.ClientAuth == "body" {
+		form.Set("client_id", s.cfg.ClientID)
+		form.Set("client_secret", s.cfg.ClientSecret)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", s.cfg.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", 0, fmt.Errorf("error creating token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if s.cfg.ClientAuth != "body" {
+		req.SetBasicAuth(url.QueryEscape(s.cfg.ClientID), url.QueryEscape(s.cfg.ClientSecret))
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", 0, fmt.Errorf("error requesting token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", 0, fmt.Errorf("error reading token response: %w", err)
+	}
+
+	var tr tokenResponse
+	decodeErr := json.Unmarshal(body, &tr)
+
+	if resp.StatusCode >= 400 {
+		if decodeErr == nil && tr.Error != "" {
+			if tr.ErrorDescription != "" {
+				return "", 0, fmt.Errorf("token endpoint returned HTTP %d: %s: %s", resp.StatusCode, tr.Error, tr.ErrorDescription)
+			}
+			return "", 0, fmt.Errorf("token endpoint returned HTTP %d: %s", resp.StatusCode, tr.Error)
+		}
+		return "", 0, fmt.Errorf("token endpoint returned HTTP %d", resp.StatusCode)
+	}
+	if decodeErr != nil {
+		return "", 0, fmt.Errorf("error parsing token response: %w", decodeErr)
+	}
+	if tr.AccessToken == "" {
+		return "", 0, fmt.Errorf("token response has no access_token")
+	}
+
+	var lifetime time.Duration
+	if tr.ExpiresIn != "" {
+		expiresIn, err := tr.ExpiresIn.Int64()
+		if err != nil {
+			return "", 0, fmt.Errorf("invalid expires_in in token response: %s", tr.ExpiresIn)
+		}
+		lifetime = time.Duration(expiresIn) * time.Second
+	}
+
+	return tr.AccessToken, lifetime, nil
+}
@@ -0,0 +1,282 @@
+package bridge
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"mcp-bridge/internal/config"
+	"mcp-bridge/internal/jsonpath"
+)
+
+// linkPattern matches one link of a Link header, e.g.
+// <https://api.example.com/items?page=2>; rel="next"
+var linkPattern = regexp.MustCompile(`<([^>]*)>((?:\s*;\s*[^;,]+)*)`)
+
+// paginator finds the pages of a paginated endpoint. A page is identified by
+// a cursor: the URL of the page for "link" pagination, and the value of the
+// cursor, page or offset query parameter otherwise.
+type paginator struct {
+	config   *config.PaginationConfig
+	baseURL  string
+	firstURL string
+}
+
+// fetchPages calls a paginated endpoint. In aggregate mode it follows the
+// next pages until there are none or a limit is reached and returns the
+// items of all pages as one array. In cursor mode it fetches the page given
+// by the cursor argument and notes the cursor of the next page.
+func (b *MCPBridge) fetchPages(ctx context.Context, endpoint APIEndpoint, args map[string]interface{}) (*APIResponse, error) {
+	// In aggregate mode a "cursor" argument is an ordinary parameter
+	cursor := ""
+	if value, ok := args[config.CursorArgument]; ok && endpoint.Pagination.Mode == "cursor" {
+		cursor = fmt.Sprintf("%v", value)
+		delete(args, config.CursorArgument)
+	}
+
+	firstURL, err := b.restClient.buildURLWithBase(endpoint, args, endpoint.BaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("error building URL: %w", err)
+	}
+	p := &paginator{config: endpoint.Pagination, baseURL: endpoint.BaseURL, firstURL: firstURL}
+
+	if p.config.Mode == "cursor" {
+		pageURL := firstURL
+		if cursor != "" {
+			if pageURL, err = p.pageURL(cursor); err != nil {
+				return nil, err
+			}
+		}
+
+		response, err := b.restClient.makeRequestToURL(ctx, endpoint, args, pageURL)
+		if err != nil || response.Error != "" || response.Request != nil {
+			return response, err
+		}
+		items, err := p.items(response)
+		if err != nil {
+			return nil, err
+		}
+		if next := p.next(pageURL, response, items); next != "" {
+			response.Notes = append(response.Notes, fmt.Sprintf("more results are available: call again with %s %q", config.CursorArgument, next))
+		}
+		return response, nil
+	}
+
+	maxPages := p.config.MaxPages
+	if maxPages <= 0 {
+		maxPages = config.DefaultMaxPages
+	}
+
+	var all []interface{}
+	var response *APIResponse
+	pages := 0
+	more := false
+	for pageURL := firstURL; ; {
+		response, err = b.restClient.makeRequestToURL(ctx, endpoint, args, pageURL)
+		if err != nil {
+			return nil, fmt.Errorf("page %d: %w", pages+1, err)
+		}
+		if response.Error != "" || response.Request != nil {
+			return response, nil
+		}
+
+		items, err := p.items(response)
+		if err != nil {
+			return nil, fmt.Errorf("page %d: %w", pages+1, err)
+		}
+		all = append(all, items...)
+		pages++
+
+		next := p.next(pageURL, response, items)
+		if maxItems := p.config.MaxItems; maxItems > 0 && len(all) >= maxItems {
+			more = len(all) > maxItems || next != ""
+			all = all[:maxItems]
+			break
+		}
+		if next == "" {
+			break
+		}
+		if pages == maxPages {
+			more = true
+			break
+		}
+		if pageURL, err = p.pageURL(next); err != nil {
+			return nil, fmt.Errorf("page %d: %w", pages+1, err)
+		}
+	}
+
+	if all == nil {
+		all = []interface{}{}
+	}
+	response.Data = all
+	response.Body = ""
+	note := fmt.Sprintf("fetched %s items from %d pages", formatCount(len(all)), pages)
+	if more {
+		note += "; more items are available"
+	}
+	response.Notes = append(response.Notes, note)
+	return response, nil
+}
+
+// aggregateSchema returns the output schema of an endpoint in aggregate mode,
+// an array of the items of the pages described by pageSchema. A schema that
+// already is an array without a matching items path is taken as the schema of
+// the aggregated items.
+func aggregateSchema(pagination *config.PaginationConfig, pageSchema map[string]interface{}) map[string]interface{} {
+	itemsSchema := pageSchema
+	if pagination.Items != "" {
+		itemsSchema = nil
+		if path, err := jsonpath.Compile(pagination.Items); err == nil {
+			itemsSchema = path.Schema(pageSchema)
+		}
+		if itemsSchema == nil && pageSchema["type"] == "array" {
+			itemsSchema = pageSchema
+		}
+	}
+
+	schema := map[string]interface{}{"type": "array"}
+	if itemsSchema != nil && itemsSchema["type"] == "array" {
+		if item, ok := itemsSchema["items"]; ok {
+			schema["items"] = item
+		}
+	}
+	return schema
+}
+
+// items returns the items of a page
+func (p *paginator) items(response *APIResponse) ([]interface{}, error) {
+	data := response.Data
+	if p.config.Items != "" {
+		path, err := jsonpath.Compile(p.config.Items)
+		if err != nil {
+			return nil, err
+		}
+		data = path.Get(data)
+	}
+
+	switch v := data.(type) {
+	case []interface{}:
+		return v, nil
+	case nil:
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("pagination items '%s' is not an array", p.config.Items)
+	}
+}
+
+// next returns the cursor of the page after the page at pageURL, or "" if
+// it is the last page
+func (p *paginator) next(pageURL string, response *APIResponse, items []interface{}) string {
+	switch p.config.Type {
+	case "link":
+		next := nextLink(response.Headers["Link"])
+		if next == "" {
+			return ""
+		}
+		base, err := url.Parse(pageURL)
+		if err != nil {
+			return ""
+		}
+		ref, err := base.Parse(next)
+		if err != nil {
+			return ""
+		}
+		return ref.String()
+
+	case "cursor":
+		path, err := jsonpath.Compile(p.config.CursorPath)
+		if err != nil {
+			return ""
+		}
+		switch v := path.Get(response.Data).(type) {
+		case nil:
+			return ""
+		case float64:
+			return strconv.FormatFloat(v, 'f', -1, 64)
+		default:
+			return fmt.Sprintf("%v", v)
+		}
+
+	case "page":
+		if len(items) == 0 {
+			return ""
+		}
+		return strconv.Itoa(queryInt(pageURL, p.config.PageParam, 1) + 1)
+
+	case "offset":
+		if len(items) == 0 {
+			return ""
+		}
+		return strconv.Itoa(queryInt(pageURL, p.config.OffsetParam, 0) + len(items))
+	}
+	return ""
+}
+
+// pageURL returns the URL of the page identified by cursor
+func (p *paginator) pageURL(cursor string) (string, error) {
+	var param string
+	switch p.config.Type {
+	case "link":
+		next, err := url.Parse(cursor)
+		if err != nil {
+			return "", fmt.Errorf("invalid %s: %w", config.CursorArgument, err)
+		}
+		base, err := url.Parse(p.baseURL)
+		if err != nil {
+			return "", err
+		}
+		// Never send credentials to another host
+		if next.Scheme != base.Scheme || next.Host != base.Host {
+			return "", fmt.Errorf("next page URL %s is not on %s://%s", cursor, base.Scheme, base.Host)
+		}
+		return next.String(), nil
+	case "cursor":
+		param = p.config.CursorParam
+	case "page":
+		param = p.config.PageParam
+	case "offset":
+		param = p.config.OffsetParam
+	}
+
+	u, err := url.Parse(p.firstURL)
+	if err != nil {
+		return "", err
+	}
+	query := u.Query()
+	query.Set(param, cursor)
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
+
+// nextLink returns the target of the rel="next" link in a Link header
+func nextLink(header string) string {
+	for _, match := range linkPattern.FindAllStringSubmatch(header, -1) {
+		for _, attr := range strings.Split(match[2], ";") {
+			name, value, ok := strings.Cut(strings.TrimSpace(attr), "=")
+			if !ok || !strings.EqualFold(strings.TrimSpace(name), "rel") {
+				continue
+			}
+			for _, rel := range strings.Fields(strings.Trim(strings.TrimSpace(value), `"`)) {
+				if strings.EqualFold(rel, "next") {
+					return match[1]
+				}
+			}
+		}
+	}
+	return ""
+}
+
+func queryInt(rawURL, name string, def int) int {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return def
+	}
+	n, err := strconv.Atoi(u.Query().Get(name))
+	if err != nil {
+		return def
+	}
+	return n
+}
@@ -0,0 +1,106 @@
+package bridge
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"mcp-bridge/internal/config"
+)
+
+// DefaultWatchInterval is how often Watch checks the config files for changes
+const DefaultWatchInterval = 2 * time.Second
+
+// Reloader re-reads the configuration and applies it to a running bridge.
+// A configuration that fails to load or validate is rejected and the
+// bridge keeps serving the previous one.
+type Reloader struct {
+	bridge  *MCPBridge
+	load    func() (*config.Config, error)
+	current *config.Config
+	mu      sync.Mutex // serializes reloads and guards current
+}
+
+// NewReloader returns a Reloader for a bridge serving cfg. load must return
+// a validated configuration.
+func NewReloader(bridge *MCPBridge, cfg *config.Config, load func() (*config.Config, error)) *Reloader {
+	return &Reloader{
+		bridge:  bridge,
+		load:    load,
+		current: cfg,
+	}
+}
+
+// Reload loads the configuration and applies it to the bridge. Failing to
+// notify clients of the new tool list is logged and does not fail the reload.
+func (r *Reloader) Reload() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.reloadLocked()
+}
+
+func (r *Reloader) reloadLocked() error {
+	cfg, err := r.load()
+	if err != nil {
+		return err
+	}
+
+	r.current = cfg
+	if err := r.bridge.ApplyConfig(cfg); err != nil {
+		// The new configuration is live, only the clients missed the news
+		log.Printf("Configuration reloaded, but clients were not notified: %v", err)
+	}
+	return nil
+}
+
+// Watch reloads the configuration when SIGHUP is received or, if interval is
+// positive, when one of its files changes. It returns when ctx is done.
+func (r *Reloader) Watch(ctx context.Context, interval time.Duration) {
+	hangup := make(chan os.Signal, 1)
+	signal.Notify(hangup, syscall.SIGHUP)
+	defer signal.Stop(hangup)
+
+	var tick <-chan time.Time
+	if interval > 0 {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	r.mu.Lock()
+	fingerprint := r.current.Fingerprint()
+	r.mu.Unlock()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hangup:
+			log.Println("Received SIGHUP, reloading configuration")
+		case <-tick:
+			r.mu.Lock()
+			changed := r.current.Fingerprint()
+			r.mu.Unlock()
+			if changed == fingerprint {
+				continue
+			}
+			// Remember the state even if the reload fails, so that a broken
+			// file is only reported once rather than on every tick
+			fingerprint = changed
+			log.Println("Configuration files changed, reloading configuration")
+		}
+
+		r.mu.Lock()
+		if err := r.reloadLocked(); err != nil {
+			log.Printf("Configuration reload failed, keeping previous configuration: %v", err)
+		} else {
+			fingerprint = r.current.Fingerprint()
+			log.Printf("Configuration reloaded")
+		}
+		r.mu.Unlock()
+	}
+}
@@ -0,0 +1,357 @@
+package bridge
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"mcp-bridge/internal/config"
+	"mcp-bridge/internal/jsonpath"
+)
+
+// transformResponse applies the response settings of an endpoint to the data
+// of a successful JSON response: select, then maxItems, then fields, exclude
+// and rename on every object of the result.
+func transformResponse(settings *config.ResponseConfig, response *APIResponse) error {
+	data := response.Data
+
+	if settings.Select != "" {
+		path, err := jsonpath.Compile(settings.Select)
+		if err != nil {
+			return err
+		}
+		data = path.Get(data)
+	}
+
+	if items, ok := data.([]interface{}); ok && settings.MaxItems > 0 && len(items) > settings.MaxItems {
+		response.Notes = append(response.Notes, fmt.Sprintf("showing %s of %s items", formatCount(settings.MaxItems), formatCount(len(items))))
+		data = items[:settings.MaxItems]
+	}
+
+	data = eachObject(data, func(object map[string]interface{}) map[string]interface{} {
+		if len(settings.Fields) > 0 {
+			picked := make(map[string]interface{})
+			for _, field := range settings.Fields {
+				pickField(picked, object, strings.Split(field, "."))
+			}
+			object = picked
+		}
+		for _, field := range settings.Exclude {
+			dropField(object, strings.Split(field, "."))
+		}
+		if len(settings.Rename) > 0 {
+			renamed := make(map[string]interface{}, len(object))
+			for key, value := range object {
+				if to, ok := settings.Rename[key]; ok {
+					key = to
+				}
+				renamed[key] = value
+			}
+			object = renamed
+		}
+		return object
+	})
+
+	response.Data = data
+	if data == nil {
+		// Nothing was selected; keep the raw body out of the result
+		response.Body = "null"
+	}
+	return nil
+}
+
+// eachObject calls fn with data if it is an object, or with every object in
+// data if it is an array, and returns data with the objects replaced
+func eachObject(data interface{}, fn func(map[string]interface{}) map[string]interface{}) interface{} {
+	switch v := data.(type) {
+	case map[string]interface{}:
+		return fn(v)
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		for i, item := range v {
+			if object, ok := item.(map[string]interface{}); ok {
+				result[i] = fn(object)
+			} else {
+				result[i] = item
+			}
+		}
+		return result
+	}
+	return data
+}
+
+// pickField copies the value at path in src to dst, descending into the
+// items of arrays along the way
+func pickField(dst, src map[string]interface{}, path []string) {
+	value, ok := src[path[0]]
+	if !ok {
+		return
+	}
+	if len(path) == 1 {
+		dst[path[0]] = value
+		return
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		child, ok := dst[path[0]].(map[string]interface{})
+		if !ok {
+			child = make(map[string]interface{})
+			dst[path[0]] = child
+		}
+		pickField(child, v, path[1:])
+	case []interface{}:
+		items, ok := dst[path[0]].([]interface{})
+		if !ok {
+			items = make([]interface{}, len(v))
+			dst[path[0]] = items
+		}
+		for i, item := range v {
+			object, ok := item.(map[string]interface{})
+			if !ok {
+				items[i] = item
+				continue
+			}
+			child, ok := items[i].(map[string]interface{})
+			if !ok {
+				child = make(map[string]interface{})
+				items[i] = child
+			}
+			pickField(child, object, path[1:])
+		}
+	}
+}
+
+// dropField removes the value at path from object, descending into the
+// items of arrays along the way
+func dropField(object map[string]interface{}, path []string) {
+	if len(path) == 1 {
+		delete(object, path[0])
+		return
+	}
+
+	switch v := object[path[0]].(type) {
+	case map[string]interface{}:
+		dropField(v, path[1:])
+	case []interface{}:
+		for _, item := range v {
+			if child, ok := item.(map[string]interface{}); ok {
+				dropField(child, path[1:])
+			}
+		}
+	}
+}
+
+// formatCount formats n with thousands separators, e.g. 2,314
+func formatCount(n int) string {
+	s := strconv.Itoa(n)
+	if n < 0 {
+		return "-" + formatCount(-n)
+	}
+	for i := len(s) - 3; i > 0; i -= 3 {
+		s = s[:i] + "," + s[i:]
+	}
+	return s
+}
+
+// truncateResponse shortens a response whose output would be larger than
+// maxBytes. JSON data keeps its structure: arrays are cut to the largest
+// number of items that fits and, if that is not enough, strings are
+// shortened too. Other bodies are cut at a character boundary. Notes tell
+// the client what was left out.
+func truncateResponse(maxBytes int, response *APIResponse) {
+	if response.Error != "" {
+		if len(response.Error) > maxBytes {
+			response.Error = fmt.Sprintf("%s… (showing the first %s of %s bytes)",
+				truncateString(response.Error, maxBytes), formatCount(maxBytes), formatCount(len(response.Error)))
+		}
+		return
+	}
+
+	if response.Data == nil {
+		if len(response.Body) > maxBytes {
+			response.Notes = append(response.Notes, fmt.Sprintf("showing the first %s of %s bytes", formatCount(maxBytes), formatCount(len(response.Body))))
+			response.Body = truncateString(response.Body, maxBytes)
+		}
+		return
+	}
+
+	// Each probe formats the shortened data, so the binary searches below
+	// measure exactly what the client receives
+	fits := func(items, chars int) bool {
+		return outputSize(shorten(response.Data, items, chars, "", nil)) <= maxBytes
+	}
+	if outputSize(response.Data) <= maxBytes {
+		return
+	}
+
+	// Keep as many array items as possible, but at least one
+	items, chars := -1, -1
+	if longest := longestArray(response.Data); longest > 0 {
+		i := sort.Search(longest, func(i int) bool { return fits(longest-i, -1) })
+		items = longest - i
+		if items == 0 {
+			items = 1
+		}
+	}
+	if !fits(items, -1) {
+		longest := longestString(response.Data)
+		i := sort.Search(longest+1, func(i int) bool { return fits(items, longest-i) })
+		chars = longest - i
+		if chars < 0 {
+			chars = 0
+		}
+	}
+
+	cuts := &truncation{arrays: make(map[string]*arrayCut)}
+	response.Data = shorten(response.Data, items, chars, "", cuts)
+	response.Notes = append(response.Notes, cuts.notes(chars)...)
+}
+
+// truncation records what shorten left out
+type truncation struct {
+	arrays  map[string]*arrayCut
+	strings int
+}
+
+type arrayCut struct {
+	shown, total, count int
+}
+
+// maxTruncationNotes limits the notes about shortened arrays
+const maxTruncationNotes = 5
+
+func (t *truncation) notes(chars int) []string {
+	paths := make([]string, 0, len(t.arrays))
+	for path := range t.arrays {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var notes []string
+	for i, path := range paths {
+		if i == maxTruncationNotes {
+			notes = append(notes, fmt.Sprintf("%d more arrays were shortened", len(paths)-i))
+			break
+		}
+		cut := t.arrays[path]
+		var note string
+		if cut.count == 1 {
+			note = fmt.Sprintf("showing %s of %s items", formatCount(cut.shown), formatCount(cut.total))
+		} else {
+			note = fmt.Sprintf("showing at most %s items of each array", formatCount(cut.shown))
+		}
+		if path != "" {
+			note = path + ": " + note
+		}
+		notes = append(notes, note)
+	}
+
+	if t.strings > 0 {
+		notes = append(notes, fmt.Sprintf("%s strings longer than %s characters were shortened", formatCount(t.strings), formatCount(chars)))
+	}
+	return notes
+}
+
+// shorten returns a copy of data with arrays cut to items and strings cut to
+// chars characters; negative limits leave them unchanged. Cuts are recorded
+// in cuts if it is not nil.
+func shorten(data interface{}, items, chars int, path string, cuts *truncation) interface{} {
+	switch v := data.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			childPath := key
+			if path != "" {
+				childPath = path + "." + key
+			}
+			result[key] = shorten(value, items, chars, childPath, cuts)
+		}
+		return result
+
+	case []interface{}:
+		if items >= 0 && len(v) > items {
+			if cuts != nil {
+				cut, ok := cuts.arrays[path]
+				if !ok {
+					cut = &arrayCut{shown: items}
+					cuts.arrays[path] = cut
+				}
+				cut.count++
+				cut.total = len(v)
+			}
+			v = v[:items]
+		}
+		result := make([]interface{}, len(v))
+		for i, value := range v {
+			result[i] = shorten(value, items, chars, path+"[*]", cuts)
+		}
+		return result
+
+	case string:
+		if chars >= 0 && utf8.RuneCountInString(v) > chars {
+			if cuts != nil {
+				cuts.strings++
+			}
+			return string([]rune(v)[:chars]) + "…"
+		}
+	}
+	return data
+}
+
+func longestArray(data interface{}) int {
+	longest := 0
+	switch v := data.(type) {
+	case map[string]interface{}:
+		for _, value := range v {
+			longest = max(longest, longestArray(value))
+		}
+	case []interface{}:
+		longest = len(v)
+		for _, value := range v {
+			longest = max(longest, longestArray(value))
+		}
+	}
+	return longest
+}
+
+func longestString(data interface{}) int {
+	longest := 0
+	switch v := data.(type) {
+	case map[string]interface{}:
+		for _, value := range v {
+			longest = max(longest, longestString(value))
+		}
+	case []interface{}:
+		for _, value := range v {
+			longest = max(longest, longestString(value))
+		}
+	case string:
+		longest = utf8.RuneCountInString(v)
+	}
+	return longest
+}
+
+// outputSize is the size of data as formatted in tool results
+func outputSize(data interface{}) int {
+	jsonData, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return 0
+	}
+	return len(jsonData)
+}
+
+// truncateString returns the longest prefix of s that is at most maxBytes
+// long and does not split a character
+func truncateString(s string, maxBytes int) string {
+	if len(s) <= maxBytes {
+		return s
+	}
+	for maxBytes > 0 && !utf8.RuneStart(s[maxBytes]) {
+		maxBytes--
+	}
+	return s[:maxBytes]
+}
@@ -2,33 +2,65 @@ package bridge
 
 import (
 	"bytes"
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"net/url"
+	"reflect"
 	"strings"
+	"sync"
 	"time"
 
 	"mcp-bridge/internal/config"
 )
 
+// defaultTimeout applies to APIs that do not configure a timeout
+const defaultTimeout = 30 * time.Second
+
+// defaultMaxResponseBytes applies to APIs that do not configure
+// limits.maxResponseBytes
+const defaultMaxResponseBytes = 10 << 20
+
 type RestClient struct {
-	httpClient *http.Client
-	headers    map[string]string
+	headers  map[string]string
+	clients  map[string]*http.Client    // per-API clients keyed by APIName
+	settings map[string]clientSettings  // what each of clients was built from
+	tokens   map[string]*tokenSource    // OAuth2 token caches, see tokenSourceFor
+	breakers map[string]*circuitBreaker // per-API circuit breakers keyed by APIName
+	mu       sync.Mutex                 // guards headers, clients, settings, tokens and breakers
+}
+
+// clientSettings are the endpoint fields an API's HTTP client is built from
+type clientSettings struct {
+	timeout int
+	client  *config.ClientConfig
 }
 
 type APIEndpoint struct {
-	Name        string             `json:"name"`
-	Description string             `json:"description"`
-	Method      string             `json:"method"`
-	Path        string             `json:"path"`
-	Parameters  []APIParameter     `json:"parameters"`
-	Headers     map[string]string  `json:"headers"`
-	APIName     string             `json:"apiName"`
-	BaseURL     string             `json:"baseUrl"`
-	Auth        []config.AuthConfig `json:"auth,omitempty"`
+	Name         string                   `json:"name"`
+	Description  string                   `json:"description"`
+	Method       string                   `json:"method"`
+	Path         string                   `json:"path"`
+	Parameters   []APIParameter           `json:"parameters"`
+	Headers      map[string]string        `json:"headers"`
+	APIName      string                   `json:"apiName"`
+	BaseURL      string                   `json:"baseUrl"`
+	Auth         []config.AuthConfig      `json:"-"`
+	Timeout      int                      `json:"timeout,omitempty"`
+	Client       *config.ClientConfig     `json:"-"`
+	DryRun       bool                     `json:"dryRun,omitempty"`
+	Annotations  *config.ToolAnnotations  `json:"-"`
+	OutputSchema map[string]interface{}   `json:"outputSchema,omitempty"`
+	Response     *config.ResponseConfig   `json:"response,omitempty"`
+	Limits       config.LimitsConfig      `json:"-"`
+	Pagination   *config.PaginationConfig `json:"pagination,omitempty"`
+	Retry        *config.RetryConfig      `json:"-"`
+	// CircuitBreaker is shared by all endpoints of the API
+	CircuitBreaker *config.CircuitBreakerConfig `json:"-"`
 }
 
 type APIParameter struct {
@@ -38,6 +70,9 @@ type APIParameter struct {
 	Description string      `json:"description"`
 	Default     interface{} `json:"default,omitempty"`
 	In          string      `json:"in"`
+	// Field is the name of a body parameter in the request body, if it
+	// differs from Name
+	Field string `json:"field,omitempty"`
 }
 
 type APIResponse struct {
@@ -46,22 +81,161 @@ type APIResponse struct {
 	Body       string            `json:"body"`
 	Data       interface{}       `json:"data,omitempty"`
 	Error      string            `json:"error,omitempty"`
+	// Notes tell the client how the data was reduced, e.g. "showing 50 of
+	// 2,314 items"
+	Notes []string `json:"notes,omitempty"`
+	// Request is the request that would have been sent, set instead of the
+	// other fields for dry-run endpoints
+	Request *RequestTrace `json:"request,omitempty"`
+	// Attempts is the number of times the request was sent
+	Attempts int `json:"attempts,omitempty"`
 }
 
 func NewRestClient() *RestClient {
 	return &RestClient{
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
-		headers: make(map[string]string),
+		headers:  make(map[string]string),
+		clients:  make(map[string]*http.Client),
+		settings: make(map[string]clientSettings),
+		tokens:   make(map[string]*tokenSource),
+		breakers: make(map[string]*circuitBreaker),
+	}
+}
+
+// httpClientFor returns the HTTP client for the endpoint's API, creating it
+// from the API's timeout and client settings on first use. Each API gets its
+// own connection pool so slow APIs do not share limits with fast ones.
+func (c *RestClient) httpClientFor(endpoint APIEndpoint) *http.Client {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if client, ok := c.clients[endpoint.APIName]; ok {
+		return client
+	}
+
+	client := newHTTPClient(endpoint.Timeout, endpoint.Client)
+	c.clients[endpoint.APIName] = client
+	c.settings[endpoint.APIName] = clientSettings{timeout: endpoint.Timeout, client: endpoint.Client}
+	return client
+}
+
+func newHTTPClient(timeoutSeconds int, cfg *config.ClientConfig) *http.Client {
+	timeout := defaultTimeout
+	if timeoutSeconds > 0 {
+		timeout = time.Duration(timeoutSeconds) * time.Second
+	}
+
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if cfg != nil {
+		if cfg.ConnectTimeout > 0 {
+			dialer.Timeout = seconds(cfg.ConnectTimeout)
+		}
+		if cfg.KeepAlive > 0 {
+			dialer.KeepAlive = seconds(cfg.KeepAlive)
+		}
+		if cfg.TLSHandshakeTimeout > 0 {
+			transport.TLSHandshakeTimeout = seconds(cfg.TLSHandshakeTimeout)
+		}
+		if cfg.ResponseHeaderTimeout > 0 {
+			transport.ResponseHeaderTimeout = seconds(cfg.ResponseHeaderTimeout)
+		}
+		if cfg.IdleConnTimeout > 0 {
+			transport.IdleConnTimeout = seconds(cfg.IdleConnTimeout)
+		}
+		if cfg.MaxIdleConns > 0 {
+			transport.MaxIdleConns = cfg.MaxIdleConns
+		}
+		if cfg.MaxIdleConnsPerHost > 0 {
+			transport.MaxIdleConnsPerHost = cfg.MaxIdleConnsPerHost
+		}
+		if cfg.MaxConnsPerHost > 0 {
+			transport.MaxConnsPerHost = cfg.MaxConnsPerHost
+		}
+		transport.DisableKeepAlives = cfg.DisableKeepAlives
+	}
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
 	}
 }
 
+func seconds(n int) time.Duration {
+	return time.Duration(n) * time.Second
+}
+
 func (c *RestClient) SetHeader(key, value string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	c.headers[key] = value
 }
 
+// Reset replaces the default headers and drops the cached HTTP clients,
+// OAuth2 tokens and circuit breakers of APIs that are gone from endpoints or
+// whose settings changed, so that requests after a configuration reload use
+// the new settings. APIs whose settings are unchanged keep their tokens and
+// breaker state. Requests already in flight finish with the old clients.
+func (c *RestClient) Reset(headers map[string]string, endpoints []APIEndpoint) {
+	apis := make(map[string][]APIEndpoint)
+	for _, endpoint := range endpoints {
+		apis[endpoint.APIName] = append(apis[endpoint.APIName], endpoint)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.headers = make(map[string]string, len(headers))
+	for key, value := range headers {
+		c.headers[key] = value
+	}
+
+	for name, client := range c.clients {
+		if api, ok := apis[name]; ok && reflect.DeepEqual(c.settings[name], clientSettings{timeout: api[0].Timeout, client: api[0].Client}) {
+			continue
+		}
+		client.CloseIdleConnections()
+		delete(c.clients, name)
+		delete(c.settings, name)
+	}
+
+	for key, source := range c.tokens {
+		name, _, _ := strings.Cut(key, "\x00")
+		if c.clients[name] != source.client || !usesOAuth2(apis[name], source.cfg) {
+			delete(c.tokens, key)
+		}
+	}
+
+	for name, breaker := range c.breakers {
+		if api, ok := apis[name]; !ok || api[0].CircuitBreaker == nil || *api[0].CircuitBreaker != breaker.cfg {
+			delete(c.breakers, name)
+		}
+	}
+}
+
+// usesOAuth2 reports whether one of endpoints authenticates with cfg
+func usesOAuth2(endpoints []APIEndpoint, cfg config.OAuth2AuthConfig) bool {
+	for _, endpoint := range endpoints {
+		for _, auth := range endpoint.Auth {
+			if auth.OAuth2 != nil && reflect.DeepEqual(*auth.OAuth2, cfg) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func (c *RestClient) MakeRequest(endpoint APIEndpoint, args map[string]interface{}) (*APIResponse, error) {
+	return c.MakeRequestWithContext(context.Background(), endpoint, args)
+}
+
+// MakeRequestWithContext is like MakeRequest but aborts the upstream request
+// when ctx is cancelled or its deadline passes.
+func (c *RestClient) MakeRequestWithContext(ctx context.Context, endpoint APIEndpoint, args map[string]interface{}) (*APIResponse, error) {
 	baseURL := endpoint.BaseURL
 	if baseURL == "" {
 		return nil, fmt.Errorf("endpoint BaseURL is required")
@@ -72,60 +246,57 @@ func (c *RestClient) MakeRequest(endpoint APIEndpoint, args map[string]interface
 		return nil, fmt.Errorf("error building URL: %w", err)
 	}
 
-	var reqBody io.Reader
+	return c.makeRequestToURL(ctx, endpoint, args, fullURL)
+}
+
+// makeRequestToURL sends the request for endpoint and args to fullURL, which
+// replaces the URL built from the endpoint's path and query parameters
+func (c *RestClient) makeRequestToURL(ctx context.Context, endpoint APIEndpoint, args map[string]interface{}, fullURL string) (*APIResponse, error) {
+	var err error
+	var jsonData []byte
 	if endpoint.Method == "POST" || endpoint.Method == "PUT" || endpoint.Method == "PATCH" {
 		bodyData := c.extractBodyData(endpoint, args)
 		if bodyData != nil {
-			jsonData, err := json.Marshal(bodyData)
+			jsonData, err = json.Marshal(bodyData)
 			if err != nil {
 				return nil, fmt.Errorf("error marshaling request body: %w", err)
 			}
-			reqBody = bytes.NewBuffer(jsonData)
 		}
 	}
 
-	req, err := http.NewRequest(endpoint.Method, fullURL, reqBody)
-	if err != nil {
-		return nil, fmt.Errorf("error creating request: %w", err)
-	}
-
-	for key, value := range c.headers {
-		req.Header.Set(key, value)
-	}
-
-	for key, value := range endpoint.Headers {
-		req.Header.Set(key, value)
-	}
-
-	if reqBody != nil {
-		req.Header.Set("Content-Type", "application/json")
-	}
-
-	for _, param := range endpoint.Parameters {
-		if param.In == "header" {
-			if value, exists := args[param.Name]; exists {
-				req.Header.Set(param.Name, fmt.Sprintf("%v", value))
-			}
+	if endpoint.DryRun {
+		req, err := c.newRequest(ctx, endpoint, args, fullURL, jsonData)
+		if err != nil {
+			return nil, err
 		}
+		return &APIResponse{Request: newRequestTrace(req, jsonData)}, nil
 	}
 
-	// Apply authentication
-	if len(endpoint.Auth) > 0 {
-		err := c.applyAuthentication(req, &endpoint.Auth[0])
-		if err != nil {
-			return nil, fmt.Errorf("error applying authentication: %w", err)
+	breaker := c.breakerFor(endpoint)
+	probe := false
+	if breaker != nil {
+		if probe, err = breaker.allow(); err != nil {
+			return nil, err
 		}
 	}
 
-	resp, err := c.httpClient.Do(req)
+	resp, attempts, err := c.doWithRetry(ctx, endpoint, func() (*http.Response, error) {
+		return c.do(ctx, endpoint, args, fullURL, jsonData)
+	})
+	if breaker != nil {
+		breaker.done(ctx, probe, resp, err)
+	}
 	if err != nil {
-		return nil, fmt.Errorf("error making request: %w", err)
+		if attempts > 1 {
+			return nil, fmt.Errorf("%w (after %d attempts)", err, attempts)
+		}
+		return nil, err
 	}
 	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
+	body, err := readBody(resp, endpoint.Limits.MaxResponseBytes)
 	if err != nil {
-		return nil, fmt.Errorf("error reading response body: %w", err)
+		return nil, err
 	}
 
 	responseHeaders := make(map[string]string)
@@ -139,6 +310,7 @@ func (c *RestClient) MakeRequest(endpoint APIEndpoint, args map[string]interface
 		StatusCode: resp.StatusCode,
 		Headers:    responseHeaders,
 		Body:       string(body),
+		Attempts:   attempts,
 	}
 
 	if resp.StatusCode >= 400 {
@@ -153,6 +325,117 @@ func (c *RestClient) MakeRequest(endpoint APIEndpoint, args map[string]interface
 	return apiResp, nil
 }
 
+// do sends one request for endpoint
+func (c *RestClient) do(ctx context.Context, endpoint APIEndpoint, args map[string]interface{}, fullURL string, jsonData []byte) (*http.Response, error) {
+	req, err := c.newRequest(ctx, endpoint, args, fullURL, jsonData)
+	if err != nil {
+		return nil, err
+	}
+
+	traceRequest(req, jsonData)
+	client := c.httpClientFor(endpoint)
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error making request: %w", err)
+	}
+
+	// An OAuth2 token can be revoked before it expires; retry once with a
+	// freshly fetched token.
+	if resp.StatusCode == http.StatusUnauthorized && c.invalidateToken(endpoint, req) {
+		resp.Body.Close()
+
+		req, err = c.newRequest(ctx, endpoint, args, fullURL, jsonData)
+		if err != nil {
+			return nil, err
+		}
+		traceRequest(req, jsonData)
+		resp, err = client.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("error making request: %w", err)
+		}
+	}
+	return resp, nil
+}
+
+// readBody reads the response body, failing if it is larger than maxBytes
+// or defaultMaxResponseBytes if maxBytes is not positive
+func readBody(resp *http.Response, maxBytes int64) ([]byte, error) {
+	if maxBytes <= 0 {
+		maxBytes = defaultMaxResponseBytes
+	}
+	tooLarge := fmt.Errorf("response body exceeds the limit of %d bytes (limits.maxResponseBytes)", maxBytes)
+	if resp.ContentLength > maxBytes {
+		return nil, tooLarge
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("error reading response body: %w", err)
+	}
+	if int64(len(body)) > maxBytes {
+		return nil, tooLarge
+	}
+	return body, nil
+}
+
+// newRequest creates the upstream request with headers and authentication
+// applied. It is called again when a request has to be resent.
+func (c *RestClient) newRequest(ctx context.Context, endpoint APIEndpoint, args map[string]interface{}, fullURL string, jsonData []byte) (*http.Request, error) {
+	var reqBody io.Reader
+	if jsonData != nil {
+		reqBody = bytes.NewReader(jsonData)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, endpoint.Method, fullURL, reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %w", err)
+	}
+
+	c.mu.Lock()
+	for key, value := range c.headers {
+		req.Header.Set(key, value)
+	}
+	c.mu.Unlock()
+
+	for key, value := range endpoint.Headers {
+		req.Header.Set(key, value)
+	}
+
+	if reqBody != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	for _, param := range endpoint.Parameters {
+		if param.In == "header" {
+			if value, exists := args[param.Name]; exists {
+				req.Header.Set(param.Name, fmt.Sprintf("%v", value))
+			}
+		}
+	}
+
+	// Apply authentication
+	if len(endpoint.Auth) > 0 {
+		err := c.applyAuthentication(req, endpoint, &endpoint.Auth[0])
+		if err != nil {
+			return nil, fmt.Errorf("error applying authentication: %w", err)
+		}
+	}
+
+	return req, nil
+}
+
+// invalidateToken drops the OAuth2 token that req was sent with and reports
+// whether the endpoint uses OAuth2, i.e. whether resending may succeed.
+func (c *RestClient) invalidateToken(endpoint APIEndpoint, req *http.Request) bool {
+	if len(endpoint.Auth) == 0 || endpoint.Auth[0].Type != "oauth2" || endpoint.Auth[0].OAuth2 == nil {
+		return false
+	}
+
+	source := c.tokenSourceFor(endpoint, endpoint.Auth[0].OAuth2)
+	source.Invalidate(strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer "))
+	return true
+}
+
 func (c *RestClient) buildURLWithBase(endpoint APIEndpoint, args map[string]interface{}, baseURL string) (string, error) {
 	path := endpoint.Path
 	queryParams := url.Values{}
@@ -177,7 +460,13 @@ func (c *RestClient) buildURLWithBase(endpoint APIEndpoint, args map[string]inte
 			placeholder := "{" + param.Name + "}"
 			path = strings.ReplaceAll(path, placeholder, url.PathEscape(fmt.Sprintf("%v", value)))
 		case "query":
-			queryParams.Add(param.Name, fmt.Sprintf("%v", value))
+			if values, ok := value.([]interface{}); ok {
+				for _, v := range values {
+					queryParams.Add(param.Name, fmt.Sprintf("%v", v))
+				}
+			} else {
+				queryParams.Add(param.Name, fmt.Sprintf("%v", value))
+			}
 		}
 	}
 
@@ -194,11 +483,15 @@ func (c *RestClient) extractBodyData(endpoint APIEndpoint, args map[string]inter
 
 	for _, param := range endpoint.Parameters {
 		if param.In == "body" {
+			field := param.Field
+			if field == "" {
+				field = param.Name
+			}
 			value, exists := args[param.Name]
 			if exists {
-				bodyData[param.Name] = value
+				bodyData[field] = value
 			} else if param.Required && param.Default != nil {
-				bodyData[param.Name] = param.Default
+				bodyData[field] = param.Default
 			}
 		}
 	}
@@ -225,20 +518,56 @@ func (c *RestClient) extractBodyData(endpoint APIEndpoint, args map[string]inter
 	return bodyData
 }
 
-func (c *RestClient) applyAuthentication(req *http.Request, auth *config.AuthConfig) error {
+func (c *RestClient) applyAuthentication(req *http.Request, endpoint APIEndpoint, auth *config.AuthConfig) error {
 	switch auth.Type {
 	case "basic":
 		if auth.Basic == nil {
 			return fmt.Errorf("basic auth configuration is nil")
 		}
-		
+
 		credentials := auth.Basic.Username + ":" + auth.Basic.Password
 		encoded := base64.StdEncoding.EncodeToString([]byte(credentials))
 		req.Header.Set("Authorization", "Basic "+encoded)
-		
+
+	case "bearer":
+		if auth.Bearer == nil {
+			return fmt.Errorf("bearer auth configuration is nil")
+		}
+
+		req.Header.Set("Authorization", "Bearer "+auth.Bearer.Token)
+
+	case "apiKey":
+		if auth.APIKey == nil {
+			return fmt.Errorf("apiKey auth configuration is nil")
+		}
+
+		switch auth.APIKey.In {
+		case "", "header":
+			req.Header.Set(auth.APIKey.Name, auth.APIKey.Value)
+		case "query":
+			query := req.URL.Query()
+			query.Set(auth.APIKey.Name, auth.APIKey.Value)
+			req.URL.RawQuery = query.Encode()
+		case "cookie":
+			req.AddCookie(&http.Cookie{Name: auth.APIKey.Name, Value: auth.APIKey.Value})
+		default:
+			return fmt.Errorf("unsupported apiKey location: %s", auth.APIKey.In)
+		}
+
+	case "oauth2":
+		if auth.OAuth2 == nil {
+			return fmt.Errorf("oauth2 auth configuration is nil")
+		}
+
+		token, err := c.tokenSourceFor(endpoint, auth.OAuth2).Token(req.Context())
+		if err != nil {
+			return fmt.Errorf("error obtaining OAuth2 token: %w", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+
 	default:
 		return fmt.Errorf("unsupported authentication type: %s", auth.Type)
 	}
-	
+
 	return nil
 }
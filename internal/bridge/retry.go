@@ -0,0 +1,96 @@
+package bridge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"mcp-bridge/internal/config"
+)
+
+// maxDrainBytes is how much of a response that is retried is read so the
+// connection can be reused
+const maxDrainBytes = 64 << 10
+
+// doWithRetry calls do until it succeeds or the retry policy of the endpoint
+// gives up. It returns the last response and the number of attempts.
+func (c *RestClient) doWithRetry(ctx context.Context, endpoint APIEndpoint, do func() (*http.Response, error)) (*http.Response, int, error) {
+	policy := endpoint.Retry
+	for attempt := 1; ; attempt++ {
+		resp, err := do()
+		if policy == nil || attempt >= policy.MaxAttempts || !config.MethodAllowed(policy.Methods, endpoint.Method) {
+			return resp, attempt, err
+		}
+
+		var delay time.Duration
+		if err != nil {
+			// Retry connection failures, but not a cancelled call or a
+			// request that could not be built
+			var urlErr *url.Error
+			if ctx.Err() != nil || !errors.As(err, &urlErr) {
+				return nil, attempt, err
+			}
+			delay = backoff(policy, attempt)
+		} else {
+			if !policy.RetriesStatus(resp.StatusCode) {
+				return resp, attempt, nil
+			}
+			delay = backoff(policy, attempt)
+			if after, ok := retryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+				if after > time.Duration(policy.MaxBackoffMs)*time.Millisecond {
+					return resp, attempt, nil
+				}
+				delay = after
+			}
+			io.Copy(io.Discard, io.LimitReader(resp.Body, maxDrainBytes))
+			resp.Body.Close()
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, attempt, fmt.Errorf("error making request: %w", ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
+
+// backoff returns the wait after the given attempt: the initial backoff
+// doubled for every earlier attempt, capped at the maximum, of which a
+// random half is taken off so that clients do not retry in lockstep
+func backoff(policy *config.RetryConfig, attempt int) time.Duration {
+	d := time.Duration(policy.InitialBackoffMs) * time.Millisecond
+	limit := time.Duration(policy.MaxBackoffMs) * time.Millisecond
+	for i := 1; i < attempt && d < limit; i++ {
+		d *= 2
+	}
+	d = min(d, limit)
+	if d <= 0 {
+		return 0
+	}
+	return d/2 + rand.N(d/2+1)
+}
+
+// retryAfter parses a Retry-After header, which is either a number of
+// seconds or an HTTP date
+func retryAfter(header string, now time.Time) (time.Duration, bool) {
+	header = strings.TrimSpace(header)
+	if header == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(header); err == nil {
+		return time.Duration(max(seconds, 0)) * time.Second, true
+	}
+	if t, err := http.ParseTime(header); err == nil {
+		return max(t.Sub(now), 0), true
+	}
+	return 0, false
+}
@@ -0,0 +1,82 @@
+package bridge
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"mcp-bridge/internal/config"
+)
+
+// RequestTrace describes an upstream request as it was sent, after
+// parameters, headers and authentication were applied
+type RequestTrace struct {
+	Method  string      `json:"method"`
+	URL     string      `json:"url"`
+	Headers http.Header `json:"headers"`
+	Body    string      `json:"body,omitempty"`
+}
+
+type traceKey struct{}
+
+// WithRequestTrace returns a context that makes the RestClient call fn with
+// every upstream request made on behalf of ctx, including requests resent
+// after a token refresh.
+func WithRequestTrace(ctx context.Context, fn func(*RequestTrace)) context.Context {
+	return context.WithValue(ctx, traceKey{}, fn)
+}
+
+func traceRequest(req *http.Request, body []byte) {
+	if fn, ok := req.Context().Value(traceKey{}).(func(*RequestTrace)); ok {
+		fn(newRequestTrace(req, body))
+	}
+}
+
+func newRequestTrace(req *http.Request, body []byte) *RequestTrace {
+	return &RequestTrace{
+		Method:  req.Method,
+		URL:     req.URL.String(),
+		Headers: req.Header.Clone(),
+		Body:    string(body),
+	}
+}
+
+// Format renders the request like an HTTP/1.1 message. Values of sensitive
+// headers are replaced by config.MaskedValue, keeping the scheme of
+// Authorization headers, and masker is applied to the whole text so that
+// secrets in the URL or body are hidden too.
+func (t *RequestTrace) Format(masker *config.Masker) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %s\n", t.Method, t.URL)
+
+	names := make([]string, 0, len(t.Headers))
+	for name := range t.Headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, value := range t.Headers[name] {
+			if config.IsSensitiveHeader(name) {
+				value = maskHeaderValue(value)
+			}
+			fmt.Fprintf(&b, "%s: %s\n", name, value)
+		}
+	}
+
+	if t.Body != "" {
+		fmt.Fprintf(&b, "\n%s\n", t.Body)
+	}
+
+	return masker.Mask(b.String())
+}
+
+// maskHeaderValue hides a credential, keeping an authentication scheme such
+// as "Bearer" so the output still shows how the request authenticated
+func maskHeaderValue(value string) string {
+	if scheme, _, ok := strings.Cut(value, " "); ok && !strings.ContainsAny(scheme, "=;") {
+		return scheme + " " + config.MaskedValue
+	}
+	return config.MaskedValue
+}
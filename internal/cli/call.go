@@ -0,0 +1,143 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"mcp-bridge/internal/bridge"
+	"mcp-bridge/internal/config"
+)
+
+var callCommand = &command{
+	name:    "call",
+	args:    "<tool> [JSON arguments]",
+	summary: "Call a tool once and print the upstream request and the result",
+	help: "Arguments are given as a JSON object, with -args-file, or with -arg flags:\n" +
+		"-arg name=value passes a string and -arg name:=value passes raw JSON.\n" +
+		"Later sources override earlier ones. Secrets in the printed request are\n" +
+		"masked. The exit status is 1 if the tool result is an error.",
+	run: runCall,
+}
+
+func runCall(e *env, fs *flag.FlagSet, args []string) error {
+	var (
+		cf       configFlags
+		argFlags stringsList
+	)
+	cf.register(fs)
+	argsFile := fs.String("args-file", "", "Read the arguments from a JSON file, or from stdin if \"-\"")
+	asJSON := fs.Bool("json", false, "Print the result as returned by tools/call")
+	quiet := fs.Bool("quiet", false, "Do not print the upstream request")
+	fs.Var(&argFlags, "arg", "Tool argument as name=value or name:=json (repeatable)")
+	if err := parseFlags(fs, args, 1, 2); err != nil {
+		return err
+	}
+
+	toolArgs, err := callArguments(*argsFile, fs.Arg(1), argFlags)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := cf.load(e)
+	if err != nil {
+		return err
+	}
+
+	mcpBridge, err := bridge.NewFromConfig(nil, cfg)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if cfg.Server.RequestTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(cfg.Server.RequestTimeout)*time.Second)
+		defer cancel()
+	}
+
+	masker := config.NewMasker(cfg.Secrets())
+	if !*quiet {
+		ctx = bridge.WithRequestTrace(ctx, func(trace *bridge.RequestTrace) {
+			fmt.Fprintf(e.stdout, "%s\n", trace.Format(masker))
+		})
+	}
+
+	result, err := mcpBridge.CallTool(ctx, fs.Arg(0), toolArgs)
+	if err != nil {
+		return err
+	}
+
+	if *asJSON {
+		data, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(e.stdout, string(data))
+	} else {
+		for _, content := range result.Content {
+			fmt.Fprintln(e.stdout, content.Text)
+		}
+	}
+
+	if result.IsError {
+		return &exitError{code: 1}
+	}
+	return nil
+}
+
+// callArguments merges the tool arguments from a JSON file, the JSON
+// argument and -arg flags, in that order
+func callArguments(argsFile, jsonArgs string, argFlags []string) (map[string]interface{}, error) {
+	toolArgs := make(map[string]interface{})
+
+	if argsFile != "" {
+		var data []byte
+		var err error
+		if argsFile == "-" {
+			data, err = io.ReadAll(os.Stdin)
+		} else {
+			data, err = os.ReadFile(argsFile)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error reading arguments: %w", err)
+		}
+		if err := json.Unmarshal(data, &toolArgs); err != nil {
+			return nil, fmt.Errorf("arguments file %s must contain a JSON object: %w", argsFile, err)
+		}
+	}
+
+	if jsonArgs != "" {
+		if err := json.Unmarshal([]byte(jsonArgs), &toolArgs); err != nil {
+			return nil, fmt.Errorf("arguments must be a JSON object: %w", err)
+		}
+	}
+
+	if toolArgs == nil {
+		// The arguments were JSON null
+		toolArgs = make(map[string]interface{})
+	}
+
+	for _, arg := range argFlags {
+		name, value, ok := strings.Cut(arg, "=")
+		if !ok || name == "" || name == ":" {
+			return nil, fmt.Errorf("invalid -arg %q: must be name=value or name:=json", arg)
+		}
+		if raw, isJSON := strings.CutSuffix(name, ":"); isJSON {
+			var decoded interface{}
+			if err := json.Unmarshal([]byte(value), &decoded); err != nil {
+				return nil, fmt.Errorf("invalid -arg %q: %w", arg, err)
+			}
+			toolArgs[raw] = decoded
+			continue
+		}
+		toolArgs[name] = value
+	}
+
+	return toolArgs, nil
+}
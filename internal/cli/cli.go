@@ -0,0 +1,168 @@
+// Package cli implements the mcpify command. Every subcommand that works
+// with a bridge configuration loads it through configFlags and builds the
+// bridge with bridge.NewFromConfig, so that validate, list-tools and call see
+// exactly what serve exposes.
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// command is a single mcpify subcommand
+type command struct {
+	name    string
+	args    string // synopsis of the arguments, for usage output
+	summary string
+	help    string // further details shown by -h
+	run     func(e *env, fs *flag.FlagSet, args []string) error
+}
+
+// env is what a running command writes to
+type env struct {
+	stdout io.Writer
+	stderr io.Writer
+}
+
+// exitError makes Run exit with a specific code without printing anything
+// further, for commands that have already reported the failure.
+type exitError struct {
+	code int
+}
+
+func (e *exitError) Error() string {
+	return fmt.Sprintf("exit status %d", e.code)
+}
+
+var commands = []*command{
+	serveCommand,
+	validateCommand,
+	listToolsCommand,
+	callCommand,
+	importCommand,
+	mockCommand,
+}
+
+// Run executes the mcpify command line given by args, which excludes the
+// program name, and returns the process exit code.
+func Run(args []string, stdout, stderr io.Writer) int {
+	e := &env{stdout: stdout, stderr: stderr}
+
+	if len(args) == 0 {
+		usage(stderr)
+		return 2
+	}
+
+	name := args[0]
+	if name == "help" || name == "-h" || name == "--help" || name == "-help" {
+		usage(stdout)
+		return 0
+	}
+
+	for _, cmd := range commands {
+		if cmd.name != name {
+			continue
+		}
+
+		err := cmd.run(e, newFlagSet(e, cmd), args[1:])
+		var exit *exitError
+		switch {
+		case err == nil:
+			return 0
+		case errors.Is(err, flag.ErrHelp):
+			return 0
+		case errors.As(err, &exit):
+			return exit.code
+		case errors.Is(err, errUsage):
+			return 2
+		default:
+			fmt.Fprintf(stderr, "mcpify %s: %v\n", name, err)
+			return 1
+		}
+	}
+
+	fmt.Fprintf(stderr, "mcpify: unknown command %q\n\n", name)
+	usage(stderr)
+	return 2
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "mcpify exposes REST APIs as MCP tools.")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Usage:")
+	fmt.Fprintln(w, "  mcpify <command> [flags] [arguments]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-11s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Run 'mcpify <command> -h' for the flags of a command.")
+}
+
+// errUsage reports invalid arguments after the flag set has printed usage
+var errUsage = errors.New("invalid usage")
+
+// newFlagSet returns a flag set for cmd that reports errors instead of
+// exiting, and prints usage to the command's stderr.
+func newFlagSet(e *env, cmd *command) *flag.FlagSet {
+	fs := flag.NewFlagSet("mcpify "+cmd.name, flag.ContinueOnError)
+	fs.SetOutput(e.stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(e.stderr, "Usage: mcpify %s [flags] %s\n\n%s\n", cmd.name, cmd.args, cmd.summary)
+		if cmd.help != "" {
+			fmt.Fprintf(e.stderr, "\n%s\n", cmd.help)
+		}
+		fmt.Fprintf(e.stderr, "\nFlags:\n")
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// parseFlags parses args and checks the number of positional arguments
+func parseFlags(fs *flag.FlagSet, args []string, minArgs, maxArgs int) error {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return errUsage
+	}
+	if fs.NArg() < minArgs || (maxArgs >= 0 && fs.NArg() > maxArgs) {
+		fmt.Fprintf(fs.Output(), "%s: wrong number of arguments\n", fs.Name())
+		fs.Usage()
+		return errUsage
+	}
+	return nil
+}
+
+// stringsFlag collects the values of a flag that may be repeated or given
+// as a comma-separated list
+type stringsFlag []string
+
+func (f *stringsFlag) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *stringsFlag) Set(value string) error {
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			*f = append(*f, item)
+		}
+	}
+	return nil
+}
+
+// stringsList collects the values of a flag that may be repeated
+type stringsList []string
+
+func (l *stringsList) String() string {
+	return strings.Join(*l, " ")
+}
+
+func (l *stringsList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
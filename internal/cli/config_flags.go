@@ -0,0 +1,77 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"log"
+
+	"mcp-bridge/internal/config"
+)
+
+// configFlags are the flags shared by every command that reads a bridge
+// configuration
+type configFlags struct {
+	path   string
+	format string
+	strict bool
+	apiURL string
+	dryRun bool
+
+	readOnly       bool
+	allowedMethods stringsFlag
+}
+
+func (f *configFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&f.path, "config", "", "Path to configuration file")
+	fs.StringVar(&f.format, "format", "", "Configuration file format: json, yaml or toml (default: from file extension)")
+	fs.BoolVar(&f.strict, "strict", false, "Reject unknown config fields and mismatched path parameters")
+	fs.StringVar(&f.apiURL, "api-url", "", "REST API base URL (overrides config)")
+	fs.BoolVar(&f.dryRun, "dry-run", false, "Return the requests tool calls would send instead of sending them (overrides config)")
+	fs.BoolVar(&f.readOnly, "read-only", false, "Only expose GET, HEAD and OPTIONS endpoints as tools (overrides config)")
+	fs.Var(&f.allowedMethods, "allowed-methods", "Only expose endpoints using these HTTP methods as tools, comma-separated (overrides config)")
+}
+
+// load reads and validates the configuration. It is called at startup and
+// on every reload, and masks the configured secrets in the log output of e.
+func (f *configFlags) load(e *env) (*config.Config, error) {
+	// Validated below, once the overrides are applied
+	cfg, err := config.LoadConfigWithOptions(f.path, config.LoadOptions{Format: f.format, Strict: f.strict, DeferValidation: true})
+	if err != nil {
+		return nil, fmt.Errorf("error loading config: %w", err)
+	}
+
+	if f.apiURL != "" && len(cfg.APIs) > 0 {
+		cfg.APIs[0].BaseURL = f.apiURL
+	}
+
+	if f.dryRun {
+		for i := range cfg.APIs {
+			cfg.APIs[i].DryRun = &config.DryRunConfig{Enabled: true}
+		}
+	}
+
+	allowedMethods := f.allowedMethods
+	if f.readOnly {
+		allowedMethods = config.SafeMethods
+	}
+	if len(allowedMethods) > 0 {
+		// Copied, so that a change to the config leaves the flag and
+		// config.SafeMethods alone
+		cfg.Server.AllowedMethods = append([]string(nil), allowedMethods...)
+		for i := range cfg.APIs {
+			cfg.APIs[i].AllowedMethods = nil
+		}
+	}
+
+	validate := cfg.Validate
+	if f.strict {
+		validate = cfg.ValidateStrict
+	}
+	if err := validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	// Keep configured secrets out of log output
+	log.SetOutput(config.NewMasker(cfg.Secrets()).Writer(e.stderr))
+	return cfg, nil
+}
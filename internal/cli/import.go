@@ -0,0 +1,89 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"mcp-bridge/internal/config"
+)
+
+var importCommand = &command{
+	name:    "import",
+	args:    "<spec>",
+	summary: "Generate a configuration from an OpenAPI, Swagger or Postman file",
+	run:     runImport,
+}
+
+func runImport(e *env, fs *flag.FlagSet, args []string) error {
+	var (
+		specType = fs.String("type", "", "Spec type: openapi, swagger or postman (default: detected)")
+		name     = fs.String("name", "", "API name (default: from the spec title)")
+		baseURL  = fs.String("base-url", "", "API base URL (default: from the spec)")
+		output   = fs.String("o", "", "Write the configuration to this file instead of stdout")
+		format   = fs.String("format", "", "Output format: json, yaml or toml (default: from the -o extension, or json)")
+
+		includeTags, excludeTags   stringsFlag
+		includePaths, excludePaths stringsFlag
+	)
+	fs.Var(&includeTags, "include-tag", "Only import operations with this tag (repeatable)")
+	fs.Var(&excludeTags, "exclude-tag", "Skip operations with this tag (repeatable)")
+	fs.Var(&includePaths, "include-path", "Only import operations whose path matches this glob (repeatable)")
+	fs.Var(&excludePaths, "exclude-path", "Skip operations whose path matches this glob (repeatable)")
+	if err := parseFlags(fs, args, 1, 1); err != nil {
+		return err
+	}
+
+	outFormat, err := config.ParseFormat(*format)
+	if err != nil {
+		return err
+	}
+
+	source := &config.ImportSource{}
+	if len(includeTags) > 0 || len(includePaths) > 0 {
+		source.Include = &config.ImportFilter{Tags: includeTags, Paths: includePaths}
+	}
+	if len(excludeTags) > 0 || len(excludePaths) > 0 {
+		source.Exclude = &config.ImportFilter{Tags: excludeTags, Paths: excludePaths}
+	}
+
+	api, err := config.ImportSpec(fs.Arg(0), *specType, source)
+	if err != nil {
+		return err
+	}
+	if *name != "" {
+		api.Name = *name
+	}
+	if *baseURL != "" {
+		api.BaseURL = *baseURL
+	}
+
+	cfg := &config.Config{
+		APIs:   []config.APIConfig{*api},
+		Server: config.ServerConfig{Description: "REST API to MCP Bridge Server"},
+	}
+	if err := cfg.Validate(); err != nil {
+		fmt.Fprintf(e.stderr, "warning: the generated configuration needs editing before use:\n%v\n", err)
+	}
+
+	if outFormat == "" && *output != "" {
+		outFormat = config.FormatFromPath(*output)
+	}
+	if outFormat == "" {
+		outFormat = config.FormatJSON
+	}
+	data, err := config.EncodeConfig(cfg, outFormat)
+	if err != nil {
+		return err
+	}
+
+	if *output == "" {
+		_, err = e.stdout.Write(data)
+		return err
+	}
+	if err := os.WriteFile(*output, data, 0644); err != nil {
+		return fmt.Errorf("error writing config file: %w", err)
+	}
+	fmt.Fprintf(e.stderr, "Imported %d endpoints into %s\n", len(api.Endpoints), *output)
+	return nil
+}
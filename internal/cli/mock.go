@@ -0,0 +1,44 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"mcp-bridge/internal/mockapi"
+)
+
+var mockCommand = &command{
+	name:    "mock",
+	summary: "Run a mock REST API for trying out the bridge",
+	run:     runMock,
+}
+
+func runMock(e *env, fs *flag.FlagSet, args []string) error {
+	var (
+		configPath = fs.String("config", os.Getenv("MOCK_CONFIG"), "Path to mock API configuration file (default: $MOCK_CONFIG or "+mockapi.DefaultConfigPath+")")
+		port       = fs.String("port", "", "Port to listen on (default: $PORT or the configured port)")
+	)
+	if err := parseFlags(fs, args, 0, 0); err != nil {
+		return err
+	}
+
+	cfg, err := mockapi.LoadConfig(*configPath)
+	if err != nil {
+		return fmt.Errorf("error loading config: %w", err)
+	}
+	mockapi.ApplyEnv(cfg)
+	if *port != "" {
+		cfg.Server.Port = *port
+	}
+
+	server := mockapi.NewServer(cfg)
+	server.PrintInfo(e.stdout)
+	if *configPath != "" {
+		fmt.Fprintf(e.stdout, "Using config file: %s\n", *configPath)
+	} else {
+		fmt.Fprintf(e.stdout, "Using default config file: %s\n", mockapi.DefaultConfigPath)
+	}
+
+	return server.ListenAndServe()
+}
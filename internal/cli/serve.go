@@ -0,0 +1,145 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"mcp-bridge/internal/bridge"
+	"mcp-bridge/internal/config"
+	"mcp-bridge/internal/transport"
+)
+
+var serveCommand = &command{
+	name:    "serve",
+	summary: "Serve the configured APIs as MCP tools over stdio or HTTP",
+	run:     runServe,
+}
+
+func runServe(e *env, fs *flag.FlagSet, args []string) error {
+	var (
+		cf         configFlags
+		transType  = fs.String("transport", "", "Transport: stdio or http (default: transport.type from the config, or stdio)")
+		verbose    = fs.Bool("verbose", false, "Enable verbose logging")
+		watch      = fs.Bool("watch", false, "Reload the configuration when its files change or on SIGHUP")
+		watchEvery = fs.Duration("watch-interval", bridge.DefaultWatchInterval, "How often to check the configuration files for changes")
+		httpHost   = fs.String("host", "localhost", "HTTP host")
+		httpPort   = fs.Int("port", 8080, "HTTP port")
+		httpCORS   = fs.Bool("cors", true, "Enable CORS for HTTP transport")
+	)
+	cf.register(fs)
+	if err := parseFlags(fs, args, 0, 0); err != nil {
+		return err
+	}
+
+	// Always log to stderr, so that logging does not interfere with the
+	// stdio transport
+	log.SetOutput(e.stderr)
+	if *verbose {
+		log.SetFlags(log.LstdFlags | log.Lshortfile)
+	}
+
+	loadConfig := func() (*config.Config, error) {
+		return cf.load(e)
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	// Command line flags take precedence over the transport section of the
+	// config file
+	set := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) { set[f.Name] = true })
+
+	kind := *transType
+	if kind == "" {
+		kind = cfg.Transport.Type
+	}
+	if kind == "" {
+		kind = "stdio"
+	}
+
+	var mcpTransport transport.Transport
+	var httpConfig *transport.HTTPConfig
+	switch kind {
+	case "stdio":
+		mcpTransport = transport.NewStdioTransport()
+	case "http":
+		httpConfig = &transport.HTTPConfig{Host: *httpHost, Port: *httpPort, CORS: *httpCORS}
+		if configured := cfg.Transport.HTTP; configured != nil {
+			if !set["host"] && configured.Host != "" {
+				httpConfig.Host = configured.Host
+			}
+			if !set["port"] && configured.Port != 0 {
+				httpConfig.Port = configured.Port
+			}
+			if !set["cors"] && configured.CORS != nil {
+				httpConfig.CORS = *configured.CORS
+			}
+		}
+		mcpTransport = transport.NewHTTPTransport(httpConfig)
+	default:
+		return fmt.Errorf("unsupported transport '%s': must be stdio or http", kind)
+	}
+
+	mcpBridge, err := bridge.NewFromConfig(mcpTransport, cfg)
+	if err != nil {
+		return fmt.Errorf("error applying configuration: %w", err)
+	}
+
+	if *watch {
+		ctx, stop := context.WithCancel(context.Background())
+		defer stop()
+		go bridge.NewReloader(mcpBridge, cfg, loadConfig).Watch(ctx, *watchEvery)
+	}
+
+	if kind == "stdio" {
+		if err := mcpBridge.Start(); err != nil {
+			return fmt.Errorf("error starting MCP bridge: %w", err)
+		}
+		return nil
+	}
+
+	return serveHTTP(mcpBridge, mcpTransport, httpConfig)
+}
+
+// serveHTTP runs the bridge until SIGINT or SIGTERM is received
+func serveHTTP(mcpBridge *bridge.MCPBridge, mcpTransport transport.Transport, httpConfig *transport.HTTPConfig) error {
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sigChan)
+
+	errChan := make(chan error, 1)
+	go func() {
+		log.Printf("Starting HTTP MCP server on %s:%d", httpConfig.Host, httpConfig.Port)
+		errChan <- mcpBridge.Start()
+	}()
+
+	select {
+	case err := <-errChan:
+		if err != nil {
+			return fmt.Errorf("error starting MCP bridge: %w", err)
+		}
+		return nil
+	case <-sigChan:
+	}
+
+	log.Println("Shutting down HTTP MCP server...")
+	if err := mcpTransport.Close(); err != nil {
+		log.Printf("Error closing transport: %v", err)
+	}
+
+	// Give in-flight requests a moment to complete
+	select {
+	case <-errChan:
+	case <-time.After(5 * time.Second):
+	}
+	return nil
+}
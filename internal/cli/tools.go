@@ -0,0 +1,53 @@
+package cli
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"mcp-bridge/internal/bridge"
+)
+
+var listToolsCommand = &command{
+	name:    "list-tools",
+	summary: "List the tools the configuration exposes",
+	run:     runListTools,
+}
+
+func runListTools(e *env, fs *flag.FlagSet, args []string) error {
+	var cf configFlags
+	cf.register(fs)
+	asJSON := fs.Bool("json", false, "Print the tools as returned by tools/list")
+	if err := parseFlags(fs, args, 0, 0); err != nil {
+		return err
+	}
+
+	cfg, err := cf.load(e)
+	if err != nil {
+		return err
+	}
+
+	mcpBridge, err := bridge.NewFromConfig(nil, cfg)
+	if err != nil {
+		return err
+	}
+	tools := mcpBridge.Tools()
+
+	if *asJSON {
+		data, err := json.MarshalIndent(map[string]interface{}{"tools": tools}, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(e.stdout, string(data))
+		return nil
+	}
+
+	w := tabwriter.NewWriter(e.stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tDESCRIPTION")
+	for _, tool := range tools {
+		fmt.Fprintf(w, "%s\t%s\n", tool.Name, strings.Join(strings.Fields(tool.Description), " "))
+	}
+	return w.Flush()
+}
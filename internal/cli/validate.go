@@ -0,0 +1,31 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+
+	"mcp-bridge/internal/bridge"
+)
+
+var validateCommand = &command{
+	name:    "validate",
+	summary: "Check a configuration file and report every problem found",
+	run:     runValidate,
+}
+
+func runValidate(e *env, fs *flag.FlagSet, args []string) error {
+	var cf configFlags
+	cf.register(fs)
+	if err := parseFlags(fs, args, 0, 0); err != nil {
+		return err
+	}
+
+	cfg, err := cf.load(e)
+	if err != nil {
+		return err
+	}
+
+	tools := len(bridge.EndpointsFromConfig(cfg))
+	fmt.Fprintf(e.stdout, "Configuration is valid: %d APIs, %d tools\n", len(cfg.APIs), tools)
+	return nil
+}
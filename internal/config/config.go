@@ -2,30 +2,296 @@ package config
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
+
+	"mcp-bridge/internal/jsonpath"
 )
 
 type Config struct {
+	Schema    string            `json:"$schema,omitempty"`
+	Include   []string          `json:"include,omitempty"`
 	APIs      []APIConfig       `json:"apis"`
 	Server    ServerConfig      `json:"server"`
 	Headers   map[string]string `json:"headers,omitempty"`
 	Transport TransportConfig   `json:"transport,omitempty"`
+
+	fileSecrets []string             // values read from ${file:...} references
+	references  map[string]reference // expanded references, restored by SaveConfig
+	format      string               // format the config was loaded from
+	sources     []string             // files the config was read from
+	patterns    []string             // include patterns, which may match new files
+	problems    []error              // unknown fields found in strict mode, reported by ValidateStrict
 }
 
 type APIConfig struct {
 	Name      string           `json:"name"`
-	BaseURL   string           `json:"baseUrl"`
-	Timeout   int              `json:"timeout"`
+	BaseURL   string           `json:"baseUrl,omitempty"`
+	Timeout   int              `json:"timeout,omitempty"`
 	Headers   map[string]string `json:"headers,omitempty"`
 	Auth      []AuthConfig     `json:"auth,omitempty"`
 	Endpoints []CustomEndpoint `json:"endpoints,omitempty"`
+	OpenAPI   *ImportSource    `json:"openapi,omitempty"`
+	Swagger   *ImportSource    `json:"swagger,omitempty"`
+	Postman   *ImportSource    `json:"postman,omitempty"`
+	Client    *ClientConfig    `json:"client,omitempty"`
+	DryRun    *DryRunConfig    `json:"dryRun,omitempty"`
+	// AllowedMethods restricts the endpoints exposed as tools to those
+	// using one of the listed HTTP methods
+	AllowedMethods []string      `json:"allowedMethods,omitempty"`
+	Limits         *LimitsConfig `json:"limits,omitempty"`
+	Retry          *RetryConfig  `json:"retry,omitempty"`
+	// CircuitBreaker stops calling the API while it is failing
+	CircuitBreaker *CircuitBreakerConfig `json:"circuitBreaker,omitempty"`
+
+	origin          apiOrigin // where the API was defined
+	importedBaseURL bool      // BaseURL was taken from an import source
+}
+
+// ClientConfig tunes the HTTP client used for an API. Durations are in
+// seconds; zero values keep the defaults.
+type ClientConfig struct {
+	ConnectTimeout        int  `json:"connectTimeout,omitempty"`
+	TLSHandshakeTimeout   int  `json:"tlsHandshakeTimeout,omitempty"`
+	ResponseHeaderTimeout int  `json:"responseHeaderTimeout,omitempty"`
+	IdleConnTimeout       int  `json:"idleConnTimeout,omitempty"`
+	KeepAlive             int  `json:"keepAlive,omitempty"`
+	DisableKeepAlives     bool `json:"disableKeepAlives,omitempty"`
+	MaxIdleConns          int  `json:"maxIdleConns,omitempty"`
+	MaxIdleConnsPerHost   int  `json:"maxIdleConnsPerHost,omitempty"`
+	MaxConnsPerHost       int  `json:"maxConnsPerHost,omitempty"`
+}
+
+// DryRunConfig makes tool calls return the request they would send instead
+// of sending it. With UnsafeOnly, GET, HEAD and OPTIONS requests are still
+// sent, so an assistant can read data but not change it.
+type DryRunConfig struct {
+	Enabled    bool `json:"enabled"`
+	UnsafeOnly bool `json:"unsafeOnly,omitempty"`
+}
+
+// Applies reports whether a request with the given method is rendered
+// rather than sent. A nil DryRunConfig applies to nothing.
+func (d *DryRunConfig) Applies(method string) bool {
+	if d == nil || !d.Enabled {
+		return false
+	}
+	return !d.UnsafeOnly || !IsSafeMethod(method)
+}
+
+// LimitsConfig caps the size of responses. MaxResponseBytes is a hard limit
+// on the upstream response body: larger responses fail. MaxOutputBytes is a
+// soft limit on the tool result, which is shortened to fit by truncating
+// arrays and strings.
+type LimitsConfig struct {
+	MaxResponseBytes int64 `json:"maxResponseBytes,omitempty"`
+	MaxOutputBytes   int   `json:"maxOutputBytes,omitempty"`
+}
+
+// RetryConfig retries failed requests. A request is sent up to MaxAttempts
+// times, counting the first attempt, when the connection fails or the
+// response status is one of Statuses. Only requests using one of Methods are
+// retried; by default those are the idempotent methods, which are safe to
+// send twice. The wait between attempts doubles from InitialBackoffMs up to
+// MaxBackoffMs, with random jitter, unless the response has a Retry-After
+// header. A response asking to wait longer than MaxBackoffMs is returned as
+// it is.
+type RetryConfig struct {
+	MaxAttempts      int      `json:"maxAttempts,omitempty"`
+	InitialBackoffMs int      `json:"initialBackoffMs,omitempty"`
+	MaxBackoffMs     int      `json:"maxBackoffMs,omitempty"`
+	Statuses         []int    `json:"statuses,omitempty"`
+	Methods          []string `json:"methods,omitempty"`
+}
+
+// Default retry settings
+const (
+	DefaultMaxAttempts      = 3
+	DefaultInitialBackoffMs = 500
+	DefaultMaxBackoffMs     = 10000
+)
+
+// DefaultRetryStatuses are the response statuses retried by default
+var DefaultRetryStatuses = []int{429, 502, 503, 504}
+
+// IdempotentMethods are the HTTP methods that can be sent more than once
+// with the same effect as sending them once
+var IdempotentMethods = []string{"GET", "HEAD", "OPTIONS", "PUT", "DELETE"}
+
+// RetriesStatus reports whether a response with status is retried
+func (r *RetryConfig) RetriesStatus(status int) bool {
+	for _, s := range r.Statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// validateRetry checks r and fills in its defaults
+func validateRetry(r *RetryConfig) []error {
+	var errs []error
+	if r.MaxAttempts < 0 || r.InitialBackoffMs < 0 || r.MaxBackoffMs < 0 {
+		errs = append(errs, fmt.Errorf("maxAttempts, initialBackoffMs and maxBackoffMs must not be negative"))
+	}
+	for _, status := range r.Statuses {
+		if status < 100 || status > 599 {
+			errs = append(errs, fmt.Errorf("invalid status %d in statuses", status))
+		}
+	}
+	for _, method := range r.Methods {
+		if !httpMethods[strings.ToUpper(method)] {
+			errs = append(errs, fmt.Errorf("unknown HTTP method '%s' in methods", method))
+		}
+	}
+
+	if r.MaxAttempts == 0 {
+		r.MaxAttempts = DefaultMaxAttempts
+	}
+	if r.InitialBackoffMs == 0 {
+		r.InitialBackoffMs = DefaultInitialBackoffMs
+	}
+	if r.MaxBackoffMs == 0 {
+		r.MaxBackoffMs = max(DefaultMaxBackoffMs, r.InitialBackoffMs)
+	}
+	if r.MaxBackoffMs < r.InitialBackoffMs {
+		errs = append(errs, fmt.Errorf("maxBackoffMs must not be less than initialBackoffMs"))
+	}
+	if len(r.Statuses) == 0 {
+		r.Statuses = append([]int(nil), DefaultRetryStatuses...)
+	}
+	if len(r.Methods) == 0 {
+		r.Methods = append([]string(nil), IdempotentMethods...)
+	}
+	return errs
+}
+
+// CircuitBreakerConfig makes tool calls fail fast while an API is down.
+// After FailureThreshold consecutive calls fail with a connection error, a
+// timeout or a 5xx status, the breaker opens and calls are rejected without
+// being sent for OpenSeconds. Then up to HalfOpenProbes calls are let through
+// as probes: if they all succeed the breaker closes, and if one fails it opens
+// again.
+type CircuitBreakerConfig struct {
+	FailureThreshold int `json:"failureThreshold,omitempty"`
+	OpenSeconds      int `json:"openSeconds,omitempty"`
+	HalfOpenProbes   int `json:"halfOpenProbes,omitempty"`
+}
+
+// Default circuit breaker settings
+const (
+	DefaultFailureThreshold = 5
+	DefaultOpenSeconds      = 30
+	DefaultHalfOpenProbes   = 1
+)
+
+// validateCircuitBreaker checks b and fills in its defaults
+func validateCircuitBreaker(b *CircuitBreakerConfig) []error {
+	var errs []error
+	if b.FailureThreshold < 0 || b.OpenSeconds < 0 || b.HalfOpenProbes < 0 {
+		errs = append(errs, fmt.Errorf("failureThreshold, openSeconds and halfOpenProbes must not be negative"))
+	}
+	if b.FailureThreshold == 0 {
+		b.FailureThreshold = DefaultFailureThreshold
+	}
+	if b.OpenSeconds == 0 {
+		b.OpenSeconds = DefaultOpenSeconds
+	}
+	if b.HalfOpenProbes == 0 {
+		b.HalfOpenProbes = DefaultHalfOpenProbes
+	}
+	return errs
+}
+
+// validatePagination checks p and fills in its defaults
+func validatePagination(p *PaginationConfig, params []CustomParameter) []error {
+	var errs []error
+	switch p.Type {
+	case "link":
+	case "cursor":
+		if p.CursorPath == "" || p.CursorParam == "" {
+			errs = append(errs, fmt.Errorf("type 'cursor' requires cursorPath and cursorParam"))
+		}
+	case "page":
+		if p.PageParam == "" {
+			p.PageParam = DefaultPageParam
+		}
+	case "offset":
+		if p.OffsetParam == "" {
+			p.OffsetParam = DefaultOffsetParam
+		}
+	default:
+		errs = append(errs, fmt.Errorf("type must be 'link', 'cursor', 'page' or 'offset', got '%s'", p.Type))
+	}
+
+	switch p.Mode {
+	case "":
+		p.Mode = "aggregate"
+	case "aggregate", "cursor":
+	default:
+		errs = append(errs, fmt.Errorf("mode must be 'aggregate' or 'cursor', got '%s'", p.Mode))
+	}
+
+	if p.Mode == "cursor" {
+		for _, param := range params {
+			if param.Name == CursorArgument {
+				errs = append(errs, fmt.Errorf("parameter '%s' conflicts with the tool argument that mode 'cursor' adds", param.Name))
+			}
+		}
+	}
+
+	for _, expr := range []string{p.Items, p.CursorPath} {
+		if _, err := jsonpath.Compile(expr); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if p.MaxPages < 0 || p.MaxItems < 0 {
+		errs = append(errs, fmt.Errorf("maxPages and maxItems must not be negative"))
+	}
+	if p.MaxPages == 0 {
+		p.MaxPages = DefaultMaxPages
+	}
+
+	return errs
+}
+
+// SafeMethods are the HTTP methods that only read data
+var SafeMethods = []string{"GET", "HEAD", "OPTIONS"}
+
+// IsSafeMethod reports whether method is one of SafeMethods
+func IsSafeMethod(method string) bool {
+	return MethodAllowed(SafeMethods, method)
+}
+
+// MethodAllowed reports whether method is in allowed, ignoring case. An
+// empty list allows every method.
+func MethodAllowed(allowed []string, method string) bool {
+	if len(allowed) == 0 {
+		return true
+	}
+	for _, m := range allowed {
+		if strings.EqualFold(m, method) {
+			return true
+		}
+	}
+	return false
+}
+
+var httpMethods = map[string]bool{
+	"GET": true, "HEAD": true, "POST": true, "PUT": true, "PATCH": true, "DELETE": true, "OPTIONS": true,
 }
 
 type AuthConfig struct {
-	Type  string           `json:"type"`
-	Basic *BasicAuthConfig `json:"basic,omitempty"`
+	Type   string            `json:"type"`
+	Basic  *BasicAuthConfig  `json:"basic,omitempty"`
+	Bearer *BearerAuthConfig `json:"bearer,omitempty"`
+	APIKey *APIKeyAuthConfig `json:"apiKey,omitempty"`
+	OAuth2 *OAuth2AuthConfig `json:"oauth2,omitempty"`
 }
 
 type BasicAuthConfig struct {
@@ -33,10 +299,44 @@ type BasicAuthConfig struct {
 	Password string `json:"password"`
 }
 
+type BearerAuthConfig struct {
+	Token string `json:"token"`
+}
+
+// APIKeyAuthConfig sends a static key as a header, query parameter or
+// cookie named Name. In defaults to "header".
+type APIKeyAuthConfig struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+	In    string `json:"in"`
+}
+
+// OAuth2AuthConfig obtains access tokens with the client credentials grant.
+// ClientAuth selects how the client authenticates to the token endpoint:
+// "basic" (HTTP Basic, the default) or "body" (client_id and client_secret
+// form parameters).
+type OAuth2AuthConfig struct {
+	TokenURL     string            `json:"tokenUrl"`
+	ClientID     string            `json:"clientId"`
+	ClientSecret string            `json:"clientSecret"`
+	Scopes       []string          `json:"scopes,omitempty"`
+	Audience     string            `json:"audience,omitempty"`
+	Params       map[string]string `json:"params,omitempty"`
+	ClientAuth   string            `json:"clientAuth,omitempty"`
+}
+
 type ServerConfig struct {
-	Name        string `json:"name"`
-	Version     string `json:"version"`
-	Description string `json:"description"`
+	Name           string `json:"name"`
+	Version        string `json:"version"`
+	Description    string `json:"description"`
+	MaxConcurrency int    `json:"maxConcurrency,omitempty"`
+	RequestTimeout int    `json:"requestTimeout,omitempty"`
+	// DryRun applies to every API that does not configure its own
+	DryRun *DryRunConfig `json:"dryRun,omitempty"`
+	// AllowedMethods applies to every API that does not configure its own
+	AllowedMethods []string `json:"allowedMethods,omitempty"`
+	// Limits apply to every API, except for the limits an API sets itself
+	Limits *LimitsConfig `json:"limits,omitempty"`
 }
 
 type TransportConfig struct {
@@ -47,7 +347,9 @@ type TransportConfig struct {
 type HTTPTransportConfig struct {
 	Host string `json:"host"`
 	Port int    `json:"port"`
-	CORS bool   `json:"cors"`
+	// CORS is nil when the config file does not set it, so that the default
+	// of the --cors flag applies
+	CORS *bool `json:"cors,omitempty"`
 }
 
 type CustomEndpoint struct {
@@ -57,8 +359,75 @@ type CustomEndpoint struct {
 	Path        string            `json:"path"`
 	Parameters  []CustomParameter `json:"parameters"`
 	Headers     map[string]string `json:"headers,omitempty"`
+	// Annotations override the tool annotations derived from Method
+	Annotations *ToolAnnotations `json:"annotations,omitempty"`
+	// OutputSchema is the JSON Schema of successful JSON responses
+	OutputSchema map[string]interface{} `json:"outputSchema,omitempty"`
+	Response     *ResponseConfig        `json:"response,omitempty"`
+	Pagination   *PaginationConfig      `json:"pagination,omitempty"`
+
+	imported bool // expanded from an import source, so not saved
 }
 
+// ResponseConfig reduces successful JSON responses before they are returned
+// to the client. Select picks part of the response with a JSONPath
+// expression, MaxItems limits the selected array, Fields and Exclude keep or
+// drop fields given as dotted paths, and Rename renames top-level fields.
+// Fields, Exclude and Rename apply to each item of an array.
+type ResponseConfig struct {
+	Select   string            `json:"select,omitempty"`
+	MaxItems int               `json:"maxItems,omitempty"`
+	Fields   []string          `json:"fields,omitempty"`
+	Exclude  []string          `json:"exclude,omitempty"`
+	Rename   map[string]string `json:"rename,omitempty"`
+}
+
+// ToolAnnotations are the MCP tool annotations of an endpoint. Unset hints
+// keep the value derived from the endpoint's HTTP method.
+type ToolAnnotations struct {
+	Title           string `json:"title,omitempty"`
+	ReadOnlyHint    *bool  `json:"readOnlyHint,omitempty"`
+	DestructiveHint *bool  `json:"destructiveHint,omitempty"`
+	IdempotentHint  *bool  `json:"idempotentHint,omitempty"`
+	OpenWorldHint   *bool  `json:"openWorldHint,omitempty"`
+}
+
+// PaginationConfig describes how a list endpoint finds its next page. Type
+// is one of
+//   - "link": the URL in the Link header with rel="next"
+//   - "cursor": the value at CursorPath in the body, sent as the query
+//     parameter CursorParam
+//   - "page": the query parameter PageParam, counting up from 1
+//   - "offset": the query parameter OffsetParam, advanced by the number of
+//     items on each page
+//
+// Items is a JSONPath selecting the items of a page; by default the whole
+// body is the array of items. In "aggregate" mode, the default, a tool call
+// fetches up to MaxPages pages and returns their items as one array, cut to
+// MaxItems. In "cursor" mode it returns one page and the cursor of the next
+// one, which the client passes back as the cursor argument.
+type PaginationConfig struct {
+	Type        string `json:"type"`
+	Mode        string `json:"mode,omitempty"`
+	Items       string `json:"items,omitempty"`
+	CursorPath  string `json:"cursorPath,omitempty"`
+	CursorParam string `json:"cursorParam,omitempty"`
+	PageParam   string `json:"pageParam,omitempty"`
+	OffsetParam string `json:"offsetParam,omitempty"`
+	MaxPages    int    `json:"maxPages,omitempty"`
+	MaxItems    int    `json:"maxItems,omitempty"`
+}
+
+// Default pagination settings
+const (
+	DefaultPageParam   = "page"
+	DefaultOffsetParam = "offset"
+	DefaultMaxPages    = 10
+)
+
+// CursorArgument is the tool argument that selects a page in cursor mode
+const CursorArgument = "cursor"
+
 type CustomParameter struct {
 	Name        string      `json:"name"`
 	Type        string      `json:"type"`
@@ -66,42 +435,128 @@ type CustomParameter struct {
 	Description string      `json:"description"`
 	Default     interface{} `json:"default,omitempty"`
 	In          string      `json:"in"`
+	// Field is the name of a body parameter in the request body, if it
+	// differs from Name
+	Field string `json:"field,omitempty"`
 }
 
+// LoadConfig reads a JSON, YAML or TOML config file, choosing the format by
+// its extension. Files without a recognized extension are read as JSON.
 func LoadConfig(configPath string) (*Config, error) {
+	return LoadConfigWithFormat(configPath, "")
+}
+
+// LoadConfigWithFormat is like LoadConfig but reads the file in the given
+// format regardless of its extension. An empty format behaves like LoadConfig.
+func LoadConfigWithFormat(configPath, format string) (*Config, error) {
+	return LoadConfigWithOptions(configPath, LoadOptions{Format: format})
+}
+
+// LoadOptions controls how LoadConfigWithOptions reads a config file
+type LoadOptions struct {
+	// Format is json, yaml or toml. Empty chooses it by file extension.
+	Format string
+	// Strict rejects unknown fields in every file read and validates the
+	// result with ValidateStrict, reporting all problems together.
+	Strict bool
+	// DeferValidation leaves calling ValidateStrict in strict mode to the
+	// caller, which can then change the config first, e.g. apply command
+	// line overrides
+	DeferValidation bool
+}
+
+// LoadConfigWithOptions reads a config file as described by opts
+func LoadConfigWithOptions(configPath string, opts LoadOptions) (*Config, error) {
+	format := opts.Format
 	if configPath == "" {
 		configPath = getDefaultConfigPath()
 	}
 
+	format, err := ParseFormat(format)
+	if err != nil {
+		return nil, err
+	}
+	if format == "" {
+		format = FormatFromPath(configPath)
+	}
+	if format == "" {
+		format = FormatJSON
+	}
+
 	if _, err := os.Stat(configPath); os.IsNotExist(err) {
 		return getDefaultConfig(), nil
 	}
 
-	data, err := os.ReadFile(configPath)
+	loader := &configLoader{seen: make(map[string]apiOrigin), strict: opts.Strict}
+	if err := loader.enter(configPath); err != nil {
+		return nil, err
+	}
+
+	doc, interp, err := loader.readFile(configPath, format)
 	if err != nil {
-		return nil, fmt.Errorf("error reading config file: %w", err)
+		return nil, err
 	}
 
 	var config Config
-	if err := json.Unmarshal(data, &config); err != nil {
-		return nil, fmt.Errorf("error parsing config file: %w", err)
+	if err := loader.decodeInto(doc, &config, configPath); err != nil {
+		return nil, err
+	}
+
+	origins := make([]apiOrigin, len(config.APIs))
+	for i := range origins {
+		origins[i] = apiOrigin{file: configPath, path: fmt.Sprintf("apis[%d]", i)}
+	}
+	if err := loader.addAPIs(config.APIs, origins); err != nil {
+		return nil, err
+	}
+
+	included, err := loader.loadIncludes(configPath, format, config.Include)
+	if err != nil {
+		return nil, err
+	}
+	config.APIs = append(config.APIs, included...)
+
+	config.fileSecrets = loader.secrets
+	config.sources = loader.files
+	config.patterns = loader.patterns
+	config.references = interp.references
+	config.format = format
+
+	if err := config.expandImports(filepath.Dir(configPath)); err != nil {
+		return nil, err
+	}
+
+	config.problems = loader.problems
+	if opts.Strict && !opts.DeferValidation {
+		if err := config.ValidateStrict(); err != nil {
+			return nil, err
+		}
 	}
 
 	return &config, nil
 }
 
+// SaveConfig writes the config in the format given by the file extension,
+// falling back to the format it was loaded in. Values that were expanded from
+// ${...} references are written as the original references, and APIs from
+// included files are not written.
 func SaveConfig(config *Config, configPath string) error {
 	if configPath == "" {
 		configPath = getDefaultConfigPath()
 	}
 
+	format := FormatFromPath(configPath)
+	if format == "" {
+		format = config.format
+	}
+
 	if err := os.MkdirAll(filepath.Dir(configPath), 0755); err != nil {
 		return fmt.Errorf("error creating config directory: %w", err)
 	}
 
-	data, err := json.MarshalIndent(config, "", "  ")
+	data, err := EncodeConfig(config, format)
 	if err != nil {
-		return fmt.Errorf("error marshaling config: %w", err)
+		return err
 	}
 
 	if err := os.WriteFile(configPath, data, 0644); err != nil {
@@ -111,6 +566,105 @@ func SaveConfig(config *Config, configPath string) error {
 	return nil
 }
 
+// EncodeConfig renders the config as SaveConfig would write it, in the given
+// format or the format it was loaded in if format is empty.
+func EncodeConfig(config *Config, format string) ([]byte, error) {
+	if format == "" {
+		format = config.format
+	}
+
+	out, err := config.withReferences()
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling config: %w", err)
+	}
+
+	jsonData, err := json.Marshal(out)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling config: %w", err)
+	}
+
+	var doc interface{}
+	if err := json.Unmarshal(jsonData, &doc); err != nil {
+		return nil, fmt.Errorf("error marshaling config: %w", err)
+	}
+
+	data, err := encodeDocument(doc, jsonData, format)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling config: %w", err)
+	}
+	return data, nil
+}
+
+// MarshalJSON leaves out the server and transport sections when they are
+// empty, so that a config saved without them is written back without them
+func (c Config) MarshalJSON() ([]byte, error) {
+	type plain Config
+	out := struct {
+		plain
+		Server    *ServerConfig    `json:"server,omitempty"`
+		Transport *TransportConfig `json:"transport,omitempty"`
+	}{plain: plain(c)}
+	if !reflect.ValueOf(c.Server).IsZero() {
+		out.Server = &c.Server
+	}
+	if !reflect.ValueOf(c.Transport).IsZero() {
+		out.Transport = &c.Transport
+	}
+	return json.Marshal(out)
+}
+
+// withReferences returns a copy of the config as it should be saved: APIs
+// from included files and endpoints from import sources are left out, and
+// expanded values are replaced by the references they came from.
+func (c *Config) withReferences() (*Config, error) {
+	own := *c
+	own.APIs = nil
+	for _, api := range c.APIs {
+		if api.origin.included {
+			continue
+		}
+		if api.importedBaseURL {
+			api.BaseURL = ""
+		}
+		endpoints := api.Endpoints
+		api.Endpoints = nil
+		for _, endpoint := range endpoints {
+			if !endpoint.imported {
+				api.Endpoints = append(api.Endpoints, endpoint)
+			}
+		}
+		own.APIs = append(own.APIs, api)
+	}
+	if c.APIs != nil && own.APIs == nil {
+		own.APIs = []APIConfig{}
+	}
+	if len(c.references) == 0 {
+		return &own, nil
+	}
+
+	data, err := json.Marshal(&own)
+	if err != nil {
+		return nil, err
+	}
+	var doc interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	doc, err = restoreReferences(doc, c.references)
+	if err != nil {
+		return nil, err
+	}
+	if data, err = json.Marshal(doc); err != nil {
+		return nil, err
+	}
+
+	var restored Config
+	if err := json.Unmarshal(data, &restored); err != nil {
+		return nil, err
+	}
+	return &restored, nil
+}
+
 func getDefaultConfigPath() string {
 	home, err := os.UserHomeDir()
 	if err != nil {
@@ -143,72 +697,211 @@ func getDefaultConfig() *Config {
 	}
 }
 
+// Validate checks the configuration and fills in defaults. All problems are
+// reported together, one per line.
 func (c *Config) Validate() error {
+	return errors.Join(c.validate(false)...)
+}
+
+// ValidateStrict is like Validate but also rejects parameter locations other
+// than path, query, header and body, and path templates whose {placeholders}
+// do not match the endpoint's path parameters. Unknown fields found when the
+// config was loaded in strict mode are reported too.
+func (c *Config) ValidateStrict() error {
+	return errors.Join(append(append([]error{}, c.problems...), c.validate(true)...)...)
+}
+
+func (c *Config) validate(strict bool) []error {
+	var errs []error
+	fail := func(format string, args ...interface{}) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+
 	if len(c.APIs) == 0 {
-		return fmt.Errorf("at least one API configuration is required")
+		fail("at least one API configuration is required")
 	}
 
 	for i, api := range c.APIs {
+		// Errors of an API loaded from a file say where it was defined
+		prefix := ""
+		if api.origin.file != "" {
+			prefix = api.origin.String() + ": "
+		}
+		fail := func(format string, args ...interface{}) {
+			errs = append(errs, fmt.Errorf("%s%s", prefix, fmt.Sprintf(format, args...)))
+		}
+
 		if api.Name == "" {
-			return fmt.Errorf("API %d: name is required", i)
+			fail("API %d: name is required", i)
 		}
 
 		if api.BaseURL == "" {
-			return fmt.Errorf("API %s: base URL is required", api.Name)
+			fail("API %s: base URL is required", api.Name)
 		}
 
 		if api.Timeout <= 0 {
 			c.APIs[i].Timeout = 30
 		}
 
-		// Validate authentication configuration
-		for j, auth := range api.Auth {
-			if auth.Type == "" {
-				return fmt.Errorf("API %s: auth type is required when auth is configured (auth index %d)", api.Name, j)
+		if client := api.Client; client != nil {
+			if client.ConnectTimeout < 0 || client.TLSHandshakeTimeout < 0 || client.ResponseHeaderTimeout < 0 ||
+				client.IdleConnTimeout < 0 || client.KeepAlive < 0 {
+				fail("API %s: client timeouts must not be negative", api.Name)
+			}
+			if client.MaxIdleConns < 0 || client.MaxIdleConnsPerHost < 0 || client.MaxConnsPerHost < 0 {
+				fail("API %s: client connection limits must not be negative", api.Name)
+			}
+		}
+
+		for _, method := range api.AllowedMethods {
+			if !httpMethods[strings.ToUpper(method)] {
+				fail("API %s: unknown HTTP method '%s' in allowedMethods", api.Name, method)
+			}
+		}
+
+		if limits := api.Limits; limits != nil && (limits.MaxResponseBytes < 0 || limits.MaxOutputBytes < 0) {
+			fail("API %s: limits must not be negative", api.Name)
+		}
+
+		if retry := api.Retry; retry != nil {
+			for _, err := range validateRetry(retry) {
+				fail("API %s: retry %v", api.Name, err)
 			}
+		}
+
+		if breaker := api.CircuitBreaker; breaker != nil {
+			for _, err := range validateCircuitBreaker(breaker) {
+				fail("API %s: circuitBreaker %v", api.Name, err)
+			}
+		}
 
+		// Validate authentication configuration
+		for j, auth := range api.Auth {
 			switch auth.Type {
+			case "":
+				fail("API %s: auth type is required when auth is configured (auth index %d)", api.Name, j)
 			case "basic":
 				if auth.Basic == nil {
-					return fmt.Errorf("API %s: basic auth configuration is required when type is 'basic' (auth index %d)", api.Name, j)
+					fail("API %s: basic auth configuration is required when type is 'basic' (auth index %d)", api.Name, j)
+					continue
 				}
 				if auth.Basic.Username == "" {
-					return fmt.Errorf("API %s: basic auth username is required (auth index %d)", api.Name, j)
+					fail("API %s: basic auth username is required (auth index %d)", api.Name, j)
 				}
 				if auth.Basic.Password == "" {
-					return fmt.Errorf("API %s: basic auth password is required (auth index %d)", api.Name, j)
+					fail("API %s: basic auth password is required (auth index %d)", api.Name, j)
+				}
+			case "bearer":
+				if auth.Bearer == nil {
+					fail("API %s: bearer auth configuration is required when type is 'bearer' (auth index %d)", api.Name, j)
+					continue
+				}
+				if auth.Bearer.Token == "" {
+					fail("API %s: bearer auth token is required (auth index %d)", api.Name, j)
+				}
+			case "apiKey":
+				if auth.APIKey == nil {
+					fail("API %s: apiKey auth configuration is required when type is 'apiKey' (auth index %d)", api.Name, j)
+					continue
+				}
+				if auth.APIKey.Name == "" {
+					fail("API %s: apiKey auth name is required (auth index %d)", api.Name, j)
+				}
+				if auth.APIKey.Value == "" {
+					fail("API %s: apiKey auth value is required (auth index %d)", api.Name, j)
+				}
+				switch auth.APIKey.In {
+				case "":
+					auth.APIKey.In = "header"
+				case "header", "query", "cookie":
+				default:
+					fail("API %s: apiKey auth location must be 'header', 'query' or 'cookie', got '%s' (auth index %d)", api.Name, auth.APIKey.In, j)
+				}
+			case "oauth2":
+				if auth.OAuth2 == nil {
+					fail("API %s: oauth2 auth configuration is required when type is 'oauth2' (auth index %d)", api.Name, j)
+					continue
+				}
+				if auth.OAuth2.TokenURL == "" {
+					fail("API %s: oauth2 token URL is required (auth index %d)", api.Name, j)
+				}
+				if auth.OAuth2.ClientID == "" {
+					fail("API %s: oauth2 client ID is required (auth index %d)", api.Name, j)
+				}
+				if auth.OAuth2.ClientSecret == "" {
+					fail("API %s: oauth2 client secret is required (auth index %d)", api.Name, j)
+				}
+				switch auth.OAuth2.ClientAuth {
+				case "":
+					auth.OAuth2.ClientAuth = "basic"
+				case "basic", "body":
+				default:
+					fail("API %s: oauth2 clientAuth must be 'basic' or 'body', got '%s' (auth index %d)", api.Name, auth.OAuth2.ClientAuth, j)
 				}
 			default:
-				return fmt.Errorf("API %s: unsupported auth type '%s' (auth index %d)", api.Name, auth.Type, j)
+				fail("API %s: unsupported auth type '%s' (auth index %d)", api.Name, auth.Type, j)
 			}
 		}
 
 		for j, endpoint := range api.Endpoints {
 			if endpoint.Name == "" {
-				return fmt.Errorf("API %s, endpoint %d: name is required", api.Name, j)
+				fail("API %s, endpoint %d: name is required", api.Name, j)
 			}
 
 			if endpoint.Method == "" {
-				return fmt.Errorf("API %s, endpoint %s: method is required", api.Name, endpoint.Name)
+				fail("API %s, endpoint %s: method is required", api.Name, endpoint.Name)
 			}
 
 			if endpoint.Path == "" {
-				return fmt.Errorf("API %s, endpoint %s: path is required", api.Name, endpoint.Name)
+				fail("API %s, endpoint %s: path is required", api.Name, endpoint.Name)
 			}
 
 			for k, param := range endpoint.Parameters {
 				if param.Name == "" {
-					return fmt.Errorf("API %s, endpoint %s, parameter %d: name is required", api.Name, endpoint.Name, k)
+					fail("API %s, endpoint %s, parameter %d: name is required", api.Name, endpoint.Name, k)
 				}
 
 				if param.In == "" {
 					api.Endpoints[j].Parameters[k].In = "query"
+				} else if strict && !validParameterLocations[param.In] {
+					fail("API %s, endpoint %s, parameter %s: location must be 'path', 'query', 'header' or 'body', got '%s'", api.Name, endpoint.Name, param.Name, param.In)
 				}
 
 				if param.Type == "" {
 					api.Endpoints[j].Parameters[k].Type = "string"
 				}
 			}
+
+			if response := endpoint.Response; response != nil {
+				if _, err := jsonpath.Compile(response.Select); err != nil {
+					fail("API %s, endpoint %s: response select: %v", api.Name, endpoint.Name, err)
+				}
+				if response.MaxItems < 0 {
+					fail("API %s, endpoint %s: response maxItems must not be negative", api.Name, endpoint.Name)
+				}
+				for _, field := range append(append([]string{}, response.Fields...), response.Exclude...) {
+					if field == "" || strings.HasPrefix(field, ".") || strings.HasSuffix(field, ".") || strings.Contains(field, "..") {
+						fail("API %s, endpoint %s: invalid response field '%s'", api.Name, endpoint.Name, field)
+					}
+				}
+				for from, to := range response.Rename {
+					if from == "" || to == "" {
+						fail("API %s, endpoint %s: response rename must map a field to a non-empty name", api.Name, endpoint.Name)
+					}
+				}
+			}
+
+			if pagination := endpoint.Pagination; pagination != nil {
+				for _, err := range validatePagination(pagination, endpoint.Parameters) {
+					fail("API %s, endpoint %s: pagination %v", api.Name, endpoint.Name, err)
+				}
+			}
+
+			if strict {
+				for _, err := range checkPathParameters(api.Name, endpoint) {
+					fail("%v", err)
+				}
+			}
 		}
 	}
 
@@ -220,7 +913,25 @@ func (c *Config) Validate() error {
 		c.Server.Version = "1.0.0"
 	}
 
+	if c.Server.MaxConcurrency < 0 {
+		fail("server maxConcurrency must not be negative")
+	}
+
+	if c.Server.RequestTimeout < 0 {
+		fail("server requestTimeout must not be negative")
+	}
+
+	if limits := c.Server.Limits; limits != nil && (limits.MaxResponseBytes < 0 || limits.MaxOutputBytes < 0) {
+		fail("server limits must not be negative")
+	}
+
+	for _, method := range c.Server.AllowedMethods {
+		if !httpMethods[strings.ToUpper(method)] {
+			fail("server: unknown HTTP method '%s' in allowedMethods", method)
+		}
+	}
+
 	// Transport configuration is optional in config file
 	// Transport type is determined by which main.go is used
-	return nil
+	return errs
 }
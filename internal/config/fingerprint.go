@@ -0,0 +1,33 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Fingerprint summarizes the state of the files the config was loaded from:
+// the config file, included files, imported specs and secret files, plus the
+// files currently matched by include patterns. It changes when any of these
+// files is modified, created or removed, so polling it detects when the
+// config needs to be reloaded.
+func (c *Config) Fingerprint() string {
+	var b strings.Builder
+
+	for _, source := range c.sources {
+		info, err := os.Stat(source)
+		if err != nil {
+			fmt.Fprintf(&b, "%s:missing\n", source)
+			continue
+		}
+		fmt.Fprintf(&b, "%s:%d:%d\n", source, info.Size(), info.ModTime().UnixNano())
+	}
+
+	for _, pattern := range c.patterns {
+		matches, _ := filepath.Glob(pattern)
+		fmt.Fprintf(&b, "%s:%s\n", pattern, strings.Join(matches, ","))
+	}
+
+	return b.String()
+}
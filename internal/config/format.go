@@ -0,0 +1,177 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// Supported configuration file formats. All of them map onto Config through
+// its JSON field names.
+const (
+	FormatJSON = "json"
+	FormatYAML = "yaml"
+	FormatTOML = "toml"
+)
+
+// FormatFromPath returns the format implied by a file extension, or "" if
+// the extension is not recognized.
+func FormatFromPath(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return FormatJSON
+	case ".yaml", ".yml":
+		return FormatYAML
+	case ".toml":
+		return FormatTOML
+	default:
+		return ""
+	}
+}
+
+// ParseFormat validates a format name given on the command line. An empty
+// name selects the format from the file extension.
+func ParseFormat(name string) (string, error) {
+	switch strings.ToLower(name) {
+	case "":
+		return "", nil
+	case "json":
+		return FormatJSON, nil
+	case "yaml", "yml":
+		return FormatYAML, nil
+	case "toml":
+		return FormatTOML, nil
+	default:
+		return "", fmt.Errorf("unsupported config format '%s': must be json, yaml or toml", name)
+	}
+}
+
+// decodeDocument parses a config file into a generic document with the same
+// shape encoding/json produces, so later steps do not depend on the format.
+func decodeDocument(data []byte, format string) (interface{}, error) {
+	var doc interface{}
+	switch format {
+	case FormatYAML:
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return nil, err
+		}
+	case FormatTOML:
+		var table map[string]interface{}
+		if err := toml.Unmarshal(data, &table); err != nil {
+			return nil, err
+		}
+		doc = table
+	default:
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return nil, err
+		}
+		return doc, nil
+	}
+
+	// Normalize YAML and TOML types (int, []map[string]interface{}, ...) to
+	// the ones encoding/json uses
+	normalized, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	doc = nil
+	if err := json.Unmarshal(normalized, &doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// encodeDocument writes a generic document decoded from JSON in the given
+// format. jsonData is the document's JSON encoding, which YAML output uses
+// to keep the field order of the Config struct.
+func encodeDocument(doc interface{}, jsonData []byte, format string) ([]byte, error) {
+	switch format {
+	case FormatYAML:
+		var node yaml.Node
+		if err := yaml.Unmarshal(jsonData, &node); err != nil {
+			return nil, err
+		}
+		blockStyle(&node)
+
+		var buf bytes.Buffer
+		encoder := yaml.NewEncoder(&buf)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(&node); err != nil {
+			return nil, err
+		}
+		if err := encoder.Close(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	case FormatTOML:
+		var buf bytes.Buffer
+		encoder := toml.NewEncoder(&buf)
+		encoder.Indent = "  "
+		if err := encoder.Encode(tomlValue(doc)); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	default:
+		var buf bytes.Buffer
+		if err := json.Indent(&buf, jsonData, "", "  "); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+}
+
+// blockStyle clears the flow style that parsing JSON as YAML leaves on
+// mappings and sequences, and the quoting of plain strings.
+func blockStyle(node *yaml.Node) {
+	switch node.Kind {
+	case yaml.MappingNode, yaml.SequenceNode:
+		if len(node.Content) > 0 {
+			node.Style = 0
+		}
+	case yaml.ScalarNode:
+		if node.Tag == "!!str" {
+			node.Style = 0
+			if strings.Contains(node.Value, "\n") {
+				node.Style = yaml.LiteralStyle
+			}
+		}
+	}
+	for _, child := range node.Content {
+		blockStyle(child)
+	}
+}
+
+// tomlValue prepares a document decoded from JSON for the TOML encoder:
+// TOML has no null, and whole numbers should stay integers.
+func tomlValue(node interface{}) interface{} {
+	switch v := node.(type) {
+	case map[string]interface{}:
+		table := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			if value != nil {
+				table[key] = tomlValue(value)
+			}
+		}
+		return table
+	case []interface{}:
+		array := make([]interface{}, 0, len(v))
+		for _, item := range v {
+			if item != nil {
+				array = append(array, tomlValue(item))
+			}
+		}
+		return array
+	case float64:
+		if v == float64(int64(v)) {
+			return int64(v)
+		}
+		return v
+	default:
+		return node
+	}
+}
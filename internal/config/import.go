@@ -0,0 +1,334 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ImportSource points an APIConfig at an external API description whose
+// operations are expanded into CustomEndpoints when the config is loaded.
+type ImportSource struct {
+	Path    string        `json:"path"`
+	Include *ImportFilter `json:"include,omitempty"`
+	Exclude *ImportFilter `json:"exclude,omitempty"`
+}
+
+// ImportFilter selects operations by tag, operation ID or path glob.
+// An operation matches the filter if it matches any of the listed values.
+type ImportFilter struct {
+	Tags         []string `json:"tags,omitempty"`
+	OperationIDs []string `json:"operationIds,omitempty"`
+	Paths        []string `json:"paths,omitempty"`
+}
+
+// importedOperation is the format-independent view of a single operation
+// used to apply include/exclude filters.
+type importedOperation struct {
+	OperationID string
+	Path        string
+	Tags        []string
+}
+
+func (f *ImportFilter) isEmpty() bool {
+	return f == nil || (len(f.Tags) == 0 && len(f.OperationIDs) == 0 && len(f.Paths) == 0)
+}
+
+func (f *ImportFilter) matches(op importedOperation) bool {
+	if f == nil {
+		return false
+	}
+
+	for _, tag := range f.Tags {
+		for _, opTag := range op.Tags {
+			if tag == opTag {
+				return true
+			}
+		}
+	}
+
+	for _, id := range f.OperationIDs {
+		if id != "" && id == op.OperationID {
+			return true
+		}
+	}
+
+	for _, pattern := range f.Paths {
+		if matchPathGlob(pattern, op.Path) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// accepts reports whether an operation passes the source's include and
+// exclude filters. Without an include filter every operation is included.
+func (s *ImportSource) accepts(op importedOperation) bool {
+	if !s.Include.isEmpty() && !s.Include.matches(op) {
+		return false
+	}
+	return !s.Exclude.matches(op)
+}
+
+// matchPathGlob matches an API path against a glob pattern. "*" matches a
+// single path segment and a trailing "/**" matches any remaining segments.
+func matchPathGlob(pattern, apiPath string) bool {
+	if prefix, ok := strings.CutSuffix(pattern, "/**"); ok {
+		depth := strings.Count(prefix, "/")
+		segments := strings.Split(apiPath, "/")
+		if len(segments) <= depth {
+			return false
+		}
+		matched, err := path.Match(prefix, strings.Join(segments[:depth+1], "/"))
+		return err == nil && matched
+	}
+
+	matched, err := path.Match(pattern, apiPath)
+	return err == nil && matched
+}
+
+// resolveImportPath resolves a spec path relative to the directory of the
+// config file that references it.
+func resolveImportPath(baseDir, specPath string) string {
+	if filepath.IsAbs(specPath) || baseDir == "" {
+		return specPath
+	}
+	return filepath.Join(baseDir, specPath)
+}
+
+// readSpecDocument reads a JSON or YAML document into generic maps.
+func readSpecDocument(specPath string) (map[string]interface{}, error) {
+	data, err := os.ReadFile(specPath)
+	if err != nil {
+		return nil, fmt.Errorf("error reading spec file: %w", err)
+	}
+
+	var doc map[string]interface{}
+	switch strings.ToLower(filepath.Ext(specPath)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &doc)
+	default:
+		err = json.Unmarshal(data, &doc)
+		if err != nil {
+			// Specs are frequently served as YAML without an extension
+			if yamlErr := yaml.Unmarshal(data, &doc); yamlErr == nil {
+				err = nil
+			}
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error parsing spec file %s: %w", specPath, err)
+	}
+	if doc == nil {
+		return nil, fmt.Errorf("spec file %s is empty", specPath)
+	}
+
+	return doc, nil
+}
+
+var invalidToolNameChars = regexp.MustCompile(`[^a-zA-Z0-9_-]+`)
+
+// toolNameFor derives a tool name from an operation ID, falling back to the
+// HTTP method and path when the spec does not provide one.
+func toolNameFor(operationID, method, apiPath string) string {
+	if operationID != "" {
+		return strings.Trim(invalidToolNameChars.ReplaceAllString(operationID, "_"), "_")
+	}
+
+	pathPart := strings.Trim(invalidToolNameChars.ReplaceAllString(apiPath, "_"), "_")
+	if pathPart == "" {
+		return strings.ToLower(method)
+	}
+	return strings.ToLower(method) + "_" + pathPart
+}
+
+// schemaTypeToParamType maps a JSON Schema type onto the parameter types
+// understood by the bridge.
+func schemaTypeToParamType(schemaType string) string {
+	switch schemaType {
+	case "integer":
+		return "integer"
+	case "number":
+		return "number"
+	case "boolean":
+		return "boolean"
+	case "array":
+		return "array"
+	case "object":
+		return "object"
+	default:
+		return "string"
+	}
+}
+
+// addParameter appends param to params unless a parameter of that name
+// exists. A body field named like a path, query or header parameter is
+// renamed to body_<name> instead of being dropped, and keeps its name in
+// the request body through Field.
+func addParameter(params []CustomParameter, param CustomParameter) []CustomParameter {
+	i := parameterIndex(params, param.Name)
+	switch {
+	case i < 0:
+	case param.In == "body" && params[i].In != "body":
+		param = renameBodyParameter(params, param)
+	case param.In != "body" && params[i].In == "body":
+		params[i] = renameBodyParameter(append(params, param), params[i])
+	default:
+		return params
+	}
+	return append(params, param)
+}
+
+func renameBodyParameter(params []CustomParameter, param CustomParameter) CustomParameter {
+	if param.Field == "" {
+		param.Field = param.Name
+	}
+	for parameterIndex(params, param.Name) >= 0 {
+		param.Name = "body_" + param.Name
+	}
+	return param
+}
+
+func parameterIndex(params []CustomParameter, name string) int {
+	for i, param := range params {
+		if param.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// addEndpoint appends an imported endpoint. Operations whose generated names
+// collide, such as /users/{id} and /users/id, are told apart by a numeric
+// suffix, e.g. get_users_id_2.
+func addEndpoint(endpoints []CustomEndpoint, endpoint CustomEndpoint) []CustomEndpoint {
+	endpoint.Name = uniqueEndpointName(endpoints, endpoint.Name)
+	return append(endpoints, endpoint)
+}
+
+func uniqueEndpointName(endpoints []CustomEndpoint, name string) string {
+	taken := func(name string) bool {
+		for _, endpoint := range endpoints {
+			if endpoint.Name == name {
+				return true
+			}
+		}
+		return false
+	}
+
+	unique := name
+	for i := 2; taken(unique); i++ {
+		unique = fmt.Sprintf("%s_%d", name, i)
+	}
+	return unique
+}
+
+// appendImportedEndpoints adds imported endpoints to an API. A hand-written
+// endpoint overrides the imported one with the same name; an endpoint
+// imported from an earlier source gets a numbered name instead.
+func appendImportedEndpoints(api *APIConfig, imported []CustomEndpoint) {
+	handWritten := make(map[string]bool, len(api.Endpoints))
+	for _, endpoint := range api.Endpoints {
+		if !endpoint.imported {
+			handWritten[endpoint.Name] = true
+		}
+	}
+
+	for _, endpoint := range imported {
+		if handWritten[endpoint.Name] {
+			continue
+		}
+		endpoint.imported = true
+		api.Endpoints = addEndpoint(api.Endpoints, endpoint)
+	}
+}
+
+// importers maps each import source kind to the function that reads it.
+var importers = []struct {
+	kind   string
+	source func(api *APIConfig) *ImportSource
+	load   func(path string, source *ImportSource) (*APIConfig, error)
+}{
+	{"OpenAPI spec", func(api *APIConfig) *ImportSource { return api.OpenAPI }, ImportOpenAPI},
+	{"Swagger spec", func(api *APIConfig) *ImportSource { return api.Swagger }, ImportSwagger},
+	{"Postman collection", func(api *APIConfig) *ImportSource { return api.Postman }, ImportPostman},
+}
+
+// expandImports adds the endpoints described by each API's import sources.
+// Relative spec paths are resolved against the directory of the file that
+// defines the API, or baseDir for APIs that were not loaded from a file.
+func (c *Config) expandImports(baseDir string) error {
+	for i := range c.APIs {
+		api := &c.APIs[i]
+
+		for _, importer := range importers {
+			source := importer.source(api)
+			if source == nil {
+				continue
+			}
+
+			specDir := baseDir
+			if api.origin.file != "" {
+				specDir = filepath.Dir(api.origin.file)
+			}
+			specPath := resolveImportPath(specDir, source.Path)
+			c.sources = append(c.sources, specPath)
+			imported, err := importer.load(specPath, source)
+			if err != nil {
+				return fmt.Errorf("API %s: error importing %s: %w", api.Name, importer.kind, err)
+			}
+			if api.BaseURL == "" {
+				api.BaseURL = imported.BaseURL
+				api.importedBaseURL = true
+			}
+			appendImportedEndpoints(api, imported.Endpoints)
+		}
+	}
+
+	return nil
+}
+
+// ImportSpec reads an OpenAPI 3, Swagger 2.0 or Postman v2 description and
+// converts it into an APIConfig. kind is "openapi", "swagger" or "postman";
+// if empty, it is detected from the document.
+func ImportSpec(specPath, kind string, source *ImportSource) (*APIConfig, error) {
+	if source == nil {
+		source = &ImportSource{}
+	}
+	source.Path = specPath
+
+	if kind == "" {
+		doc, err := readSpecDocument(specPath)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case doc["openapi"] != nil:
+			kind = "openapi"
+		case doc["swagger"] != nil:
+			kind = "swagger"
+		case doc["info"] != nil && doc["item"] != nil:
+			kind = "postman"
+		default:
+			return nil, fmt.Errorf("%s is not an OpenAPI, Swagger or Postman document", specPath)
+		}
+	}
+
+	switch kind {
+	case "openapi":
+		return ImportOpenAPI(specPath, source)
+	case "swagger":
+		return ImportSwagger(specPath, source)
+	case "postman":
+		return ImportPostman(specPath, source)
+	default:
+		return nil, fmt.Errorf("unsupported spec type '%s': must be openapi, swagger or postman", kind)
+	}
+}
@@ -0,0 +1,207 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+)
+
+// apiOrigin records where an API was defined, for error messages and so that
+// SaveConfig writes included APIs back to their own files rather than inline.
+type apiOrigin struct {
+	file     string // config file path, relative to the working directory
+	path     string // JSON path of the API within the file, e.g. apis[2]
+	included bool
+}
+
+func (o apiOrigin) String() string {
+	if o.path == "" {
+		return o.file
+	}
+	return o.file + ": " + o.path
+}
+
+// configLoader reads a config file and the files it includes
+type configLoader struct {
+	stack    []string // absolute paths of the files being loaded, for cycle detection
+	secrets  []string
+	seen     map[string]apiOrigin // API names already defined
+	files    []string             // every file read, see Config.Fingerprint
+	patterns []string             // include patterns, resolved against their file
+	strict   bool                 // collect unknown fields into problems
+	problems []error
+}
+
+// readFile decodes a config file and expands its ${...} references. The
+// interpolator is returned so the root file's references can be kept.
+func (l *configLoader) readFile(configPath, format string) (interface{}, *interpolator, error) {
+	l.files = append(l.files, configPath)
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error reading config file %s: %w", configPath, err)
+	}
+
+	doc, err := decodeDocument(data, format)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error parsing config file %s: %w", configPath, err)
+	}
+
+	interp := &interpolator{baseDir: filepath.Dir(configPath)}
+	doc, err = interp.interpolate(doc)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error interpolating config file %s: %w", configPath, err)
+	}
+	l.secrets = append(l.secrets, interp.secrets...)
+	l.files = append(l.files, interp.files...)
+
+	return doc, interp, nil
+}
+
+// decodeInto converts a decoded document to a typed value. In strict mode,
+// keys that do not map to a field of v are recorded as problems.
+func (l *configLoader) decodeInto(doc interface{}, v interface{}, configPath string) error {
+	if l.strict {
+		l.problems = append(l.problems, unknownFields(doc, reflect.TypeOf(v), configPath)...)
+	}
+
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return fmt.Errorf("error parsing config file %s: %w", configPath, err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("error parsing config file %s: %w", configPath, err)
+	}
+	return nil
+}
+
+// enter marks a file as being loaded, failing if it is already on the stack
+func (l *configLoader) enter(configPath string) error {
+	abs, err := filepath.Abs(configPath)
+	if err != nil {
+		return err
+	}
+	for i, loading := range l.stack {
+		if loading == abs {
+			cycle := append(append([]string{}, l.stack[i:]...), abs)
+			return fmt.Errorf("include cycle: %s", strings.Join(cycle, " -> "))
+		}
+	}
+	l.stack = append(l.stack, abs)
+	return nil
+}
+
+func (l *configLoader) leave() {
+	l.stack = l.stack[:len(l.stack)-1]
+}
+
+// addAPIs registers APIs defined in a file, rejecting names that are
+// already defined elsewhere.
+func (l *configLoader) addAPIs(apis []APIConfig, origins []apiOrigin) error {
+	for i := range apis {
+		apis[i].origin = origins[i]
+		if apis[i].Name == "" {
+			continue
+		}
+		if previous, ok := l.seen[apis[i].Name]; ok {
+			return fmt.Errorf("%s: duplicate API name '%s', already defined in %s", origins[i], apis[i].Name, previous)
+		}
+		l.seen[apis[i].Name] = origins[i]
+	}
+	return nil
+}
+
+// loadIncludes reads the files matched by the include patterns of the file
+// at configPath and returns the APIs they define. Patterns are relative to
+// the including file.
+func (l *configLoader) loadIncludes(configPath, format string, include []string) ([]APIConfig, error) {
+	var apis []APIConfig
+	baseDir := filepath.Dir(configPath)
+
+	for i, pattern := range include {
+		location := fmt.Sprintf("%s: include[%d]", configPath, i)
+		if !filepath.IsAbs(pattern) {
+			pattern = filepath.Join(baseDir, pattern)
+		}
+		l.patterns = append(l.patterns, pattern)
+
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("%s: invalid pattern %q: %w", location, include[i], err)
+		}
+		if len(matches) == 0 && !strings.ContainsAny(include[i], "*?[") {
+			return nil, fmt.Errorf("%s: included file %s does not exist", location, pattern)
+		}
+
+		for _, match := range matches {
+			if err := l.enter(match); err != nil {
+				return nil, fmt.Errorf("%s: %w", location, err)
+			}
+			included, err := l.loadIncludedFile(match, format)
+			l.leave()
+			if err != nil {
+				return nil, err
+			}
+			apis = append(apis, included...)
+		}
+	}
+
+	return apis, nil
+}
+
+// loadIncludedFile reads an included file, which either holds a single API
+// object or an object with "apis" and optionally its own "include" list.
+func (l *configLoader) loadIncludedFile(configPath, parentFormat string) ([]APIConfig, error) {
+	format := FormatFromPath(configPath)
+	if format == "" {
+		format = parentFormat
+	}
+
+	doc, _, err := l.readFile(configPath, format)
+	if err != nil {
+		return nil, err
+	}
+
+	fields, ok := doc.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s: included file must contain an object", configPath)
+	}
+
+	_, hasAPIs := fields["apis"]
+	_, hasInclude := fields["include"]
+	if !hasAPIs && !hasInclude {
+		var api APIConfig
+		if err := l.decodeInto(doc, &api, configPath); err != nil {
+			return nil, err
+		}
+		apis := []APIConfig{api}
+		if err := l.addAPIs(apis, []apiOrigin{{file: configPath, included: true}}); err != nil {
+			return nil, err
+		}
+		return apis, nil
+	}
+
+	var file struct {
+		APIs    []APIConfig `json:"apis"`
+		Include []string    `json:"include"`
+	}
+	if err := l.decodeInto(doc, &file, configPath); err != nil {
+		return nil, err
+	}
+
+	origins := make([]apiOrigin, len(file.APIs))
+	for i := range origins {
+		origins[i] = apiOrigin{file: configPath, path: fmt.Sprintf("apis[%d]", i), included: true}
+	}
+	if err := l.addAPIs(file.APIs, origins); err != nil {
+		return nil, err
+	}
+
+	nested, err := l.loadIncludes(configPath, format, file.Include)
+	if err != nil {
+		return nil, err
+	}
+	return append(file.APIs, nested...), nil
+}
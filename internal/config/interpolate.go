@@ -0,0 +1,156 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// referencePattern matches ${...} references and the $${ escape
+var referencePattern = regexp.MustCompile(`\$\$\{|\$\{([^}]*)\}`)
+
+// interpolator expands ${ENV}, ${ENV:-default} and ${file:/path} references
+// in the string values of a decoded config document.
+type interpolator struct {
+	baseDir    string
+	secrets    []string             // values read from files, which are always masked
+	files      []string             // secret files read
+	references map[string]reference // expanded strings by document path
+}
+
+// reference records the original text of an expanded string, so that saving
+// the config writes the reference rather than the resolved value.
+type reference struct {
+	template string
+	value    string
+}
+
+// interpolate expands references in every string of doc in place. Relative
+// file paths are resolved against baseDir.
+func (in *interpolator) interpolate(doc interface{}) (interface{}, error) {
+	in.references = make(map[string]reference)
+	return walkStrings(doc, "", func(path, s string) (string, error) {
+		expanded, err := in.expand(s)
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", path, err)
+		}
+		if expanded != s {
+			in.references[path] = reference{template: s, value: expanded}
+		}
+		return expanded, nil
+	})
+}
+
+// restoreReferences replaces strings in doc that still hold the value a
+// reference expanded to with the reference itself.
+func restoreReferences(doc interface{}, references map[string]reference) (interface{}, error) {
+	if len(references) == 0 {
+		return doc, nil
+	}
+	return walkStrings(doc, "", func(path, s string) (string, error) {
+		if ref, ok := references[path]; ok && ref.value == s {
+			return ref.template, nil
+		}
+		return s, nil
+	})
+}
+
+// walkStrings calls fn for every string in a decoded document, replacing it
+// with the result. Paths look like apis[0].headers.X-API-Key.
+func walkStrings(node interface{}, path string, fn func(path, s string) (string, error)) (interface{}, error) {
+	switch v := node.(type) {
+	case string:
+		return fn(path, v)
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			childPath := key
+			if path != "" {
+				childPath = path + "." + key
+			}
+			expanded, err := walkStrings(v[key], childPath, fn)
+			if err != nil {
+				return nil, err
+			}
+			v[key] = expanded
+		}
+		return v, nil
+	case []interface{}:
+		for i, item := range v {
+			expanded, err := walkStrings(item, fmt.Sprintf("%s[%d]", path, i), fn)
+			if err != nil {
+				return nil, err
+			}
+			v[i] = expanded
+		}
+		return v, nil
+	default:
+		return node, nil
+	}
+}
+
+func (in *interpolator) expand(s string) (string, error) {
+	if !strings.Contains(s, "${") {
+		return s, nil
+	}
+
+	var expandErr error
+	result := referencePattern.ReplaceAllStringFunc(s, func(match string) string {
+		if expandErr != nil {
+			return match
+		}
+		if match == "$${" {
+			return "${"
+		}
+		value, err := in.resolve(match[2 : len(match)-1])
+		if err != nil {
+			expandErr = err
+			return match
+		}
+		return value
+	})
+	if expandErr != nil {
+		return "", expandErr
+	}
+	return result, nil
+}
+
+// resolve returns the value of a single reference without its ${ } delimiters
+func (in *interpolator) resolve(ref string) (string, error) {
+	if path, ok := strings.CutPrefix(ref, "file:"); ok {
+		if path == "" {
+			return "", fmt.Errorf("empty file reference")
+		}
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(in.baseDir, path)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("error reading secret file: %w", err)
+		}
+		value := strings.TrimRight(string(data), "\r\n")
+		in.secrets = append(in.secrets, value)
+		in.files = append(in.files, path)
+		return value, nil
+	}
+
+	name, defaultValue, hasDefault := strings.Cut(ref, ":-")
+	if name == "" {
+		return "", fmt.Errorf("empty variable reference")
+	}
+	value, ok := os.LookupEnv(name)
+	if hasDefault && value == "" {
+		return defaultValue, nil
+	}
+	if !ok {
+		return "", fmt.Errorf("environment variable %s is not set", name)
+	}
+	return value, nil
+}
@@ -0,0 +1,499 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// openAPIMethods lists the operation keys of an OpenAPI path item in the
+// order endpoints are generated.
+var openAPIMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS"}
+
+// specResolver resolves local JSON references within a parsed spec document.
+type specResolver struct {
+	root map[string]interface{}
+}
+
+// ImportOpenAPI reads an OpenAPI 3.x document and converts its operations
+// into an APIConfig. When source is non-nil its include and exclude filters
+// decide which operations become endpoints.
+func ImportOpenAPI(specPath string, source *ImportSource) (*APIConfig, error) {
+	doc, err := readSpecDocument(specPath)
+	if err != nil {
+		return nil, err
+	}
+
+	version, _ := doc["openapi"].(string)
+	if !strings.HasPrefix(version, "3.") {
+		return nil, fmt.Errorf("%s is not an OpenAPI 3.x document", specPath)
+	}
+
+	r := &specResolver{root: doc}
+	api := &APIConfig{
+		Name:      openAPITitle(doc),
+		BaseURL:   openAPIServerURL(doc),
+		Endpoints: []CustomEndpoint{},
+	}
+
+	paths, _ := doc["paths"].(map[string]interface{})
+	for _, apiPath := range sortedKeys(paths) {
+		item, err := r.resolve(paths[apiPath])
+		if err != nil {
+			return nil, fmt.Errorf("path %s: %w", apiPath, err)
+		}
+		if item == nil {
+			continue
+		}
+
+		for _, method := range openAPIMethods {
+			op, ok := item[strings.ToLower(method)].(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			operationID, _ := op["operationId"].(string)
+			if source != nil && !source.accepts(importedOperation{
+				OperationID: operationID,
+				Path:        apiPath,
+				Tags:        stringSlice(op["tags"]),
+			}) {
+				continue
+			}
+
+			endpoint, err := r.openAPIEndpoint(method, apiPath, item, op)
+			if err != nil {
+				return nil, fmt.Errorf("%s %s: %w", method, apiPath, err)
+			}
+			api.Endpoints = addEndpoint(api.Endpoints, *endpoint)
+		}
+	}
+
+	return api, nil
+}
+
+func (r *specResolver) openAPIEndpoint(method, apiPath string, item, op map[string]interface{}) (*CustomEndpoint, error) {
+	operationID, _ := op["operationId"].(string)
+	endpoint := &CustomEndpoint{
+		Name:        toolNameFor(operationID, method, apiPath),
+		Description: operationDescription(op),
+		Method:      method,
+		Path:        apiPath,
+		Parameters:  []CustomParameter{},
+	}
+
+	// Operation-level parameters override path-level ones with the same name and location
+	var rawParams []interface{}
+	if params, ok := item["parameters"].([]interface{}); ok {
+		rawParams = append(rawParams, params...)
+	}
+	if params, ok := op["parameters"].([]interface{}); ok {
+		rawParams = append(rawParams, params...)
+	}
+
+	index := make(map[string]int)
+	for _, raw := range rawParams {
+		param, err := r.openAPIParameter(raw)
+		if err != nil {
+			return nil, err
+		}
+		if param == nil {
+			continue
+		}
+
+		key := param.In + ":" + param.Name
+		if i, exists := index[key]; exists {
+			endpoint.Parameters[i] = *param
+			continue
+		}
+		index[key] = len(endpoint.Parameters)
+		endpoint.Parameters = append(endpoint.Parameters, *param)
+	}
+
+	bodyParams, err := r.openAPIBodyParameters(op["requestBody"])
+	if err != nil {
+		return nil, fmt.Errorf("requestBody: %w", err)
+	}
+	for _, param := range bodyParams {
+		endpoint.Parameters = addParameter(endpoint.Parameters, param)
+	}
+
+	if endpoint.OutputSchema, err = r.openAPIOutputSchema(op["responses"]); err != nil {
+		return nil, fmt.Errorf("responses: %w", err)
+	}
+
+	return endpoint, nil
+}
+
+// openAPIOutputSchema returns the schema of the JSON content of an
+// operation's successful response.
+func (r *specResolver) openAPIOutputSchema(responses interface{}) (map[string]interface{}, error) {
+	response, err := r.successResponse(responses)
+	if err != nil || response == nil {
+		return nil, err
+	}
+
+	content, _ := response["content"].(map[string]interface{})
+	mediaType := preferredMediaType(content)
+	if !strings.Contains(mediaType, "json") {
+		return nil, nil
+	}
+	media, _ := content[mediaType].(map[string]interface{})
+
+	return r.inlineSchema(media["schema"])
+}
+
+// successResponse resolves the response with the lowest 2xx status code of
+// a responses object.
+func (r *specResolver) successResponse(raw interface{}) (map[string]interface{}, error) {
+	responses, _ := raw.(map[string]interface{})
+	for _, code := range sortedKeys(responses) {
+		if strings.HasPrefix(code, "2") {
+			return r.resolve(responses[code])
+		}
+	}
+	return nil, nil
+}
+
+// inlineSchema copies a schema with every $ref replaced by its target, so
+// that it can be used outside the spec. Recursive references are replaced
+// by an empty schema, which accepts any value.
+func (r *specResolver) inlineSchema(node interface{}) (map[string]interface{}, error) {
+	inlined, err := r.inline(node, make(map[string]bool))
+	if err != nil {
+		return nil, err
+	}
+	schema, _ := inlined.(map[string]interface{})
+	return schema, nil
+}
+
+func (r *specResolver) inline(node interface{}, active map[string]bool) (interface{}, error) {
+	switch v := node.(type) {
+	case map[string]interface{}:
+		if ref, ok := v["$ref"].(string); ok {
+			if active[ref] {
+				return map[string]interface{}{}, nil
+			}
+			target, err := r.lookup(ref)
+			if err != nil {
+				return nil, err
+			}
+			active[ref] = true
+			defer delete(active, ref)
+			return r.inline(target, active)
+		}
+
+		result := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			inlined, err := r.inline(value, active)
+			if err != nil {
+				return nil, err
+			}
+			result[key] = inlined
+		}
+		return result, nil
+
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		for i, value := range v {
+			inlined, err := r.inline(value, active)
+			if err != nil {
+				return nil, err
+			}
+			result[i] = inlined
+		}
+		return result, nil
+	}
+
+	return node, nil
+}
+
+func (r *specResolver) openAPIParameter(raw interface{}) (*CustomParameter, error) {
+	p, err := r.resolve(raw)
+	if err != nil {
+		return nil, err
+	}
+	if p == nil {
+		return nil, nil
+	}
+
+	name, _ := p["name"].(string)
+	in, _ := p["in"].(string)
+	if name == "" {
+		return nil, fmt.Errorf("parameter without a name")
+	}
+
+	switch in {
+	case "path", "query", "header":
+	default:
+		// Cookie parameters cannot be expressed as bridge parameters
+		return nil, nil
+	}
+
+	schema, err := r.resolve(p["schema"])
+	if err != nil {
+		return nil, fmt.Errorf("parameter %s: %w", name, err)
+	}
+
+	required, _ := p["required"].(bool)
+	description, _ := p["description"].(string)
+	param := &CustomParameter{
+		Name:        name,
+		Type:        r.schemaType(schema),
+		Required:    required || in == "path",
+		Description: description,
+		In:          in,
+	}
+	if schema != nil {
+		param.Default = schema["default"]
+	}
+
+	return param, nil
+}
+
+// openAPIBodyParameters flattens the top-level properties of a JSON request
+// body schema into body parameters.
+func (r *specResolver) openAPIBodyParameters(raw interface{}) ([]CustomParameter, error) {
+	body, err := r.resolve(raw)
+	if err != nil || body == nil {
+		return nil, err
+	}
+
+	content, _ := body["content"].(map[string]interface{})
+	media, _ := content[preferredMediaType(content)].(map[string]interface{})
+	if media == nil {
+		return nil, nil
+	}
+
+	schema, err := r.resolve(media["schema"])
+	if err != nil {
+		return nil, err
+	}
+
+	return r.schemaBodyParameters(schema)
+}
+
+// schemaBodyParameters converts the properties of an object schema into
+// body parameters.
+func (r *specResolver) schemaBodyParameters(schema map[string]interface{}) ([]CustomParameter, error) {
+	properties, required, err := r.objectProperties(schema)
+	if err != nil {
+		return nil, err
+	}
+
+	params := []CustomParameter{}
+	for _, name := range sortedKeys(properties) {
+		prop, err := r.resolve(properties[name])
+		if err != nil {
+			return nil, fmt.Errorf("property %s: %w", name, err)
+		}
+
+		description, _ := prop["description"].(string)
+		params = append(params, CustomParameter{
+			Name:        name,
+			Type:        r.schemaType(prop),
+			Required:    required[name],
+			Description: description,
+			Default:     prop["default"],
+			In:          "body",
+		})
+	}
+
+	return params, nil
+}
+
+// objectProperties collects the properties and required names of an object
+// schema, merging any allOf members.
+func (r *specResolver) objectProperties(schema map[string]interface{}) (map[string]interface{}, map[string]bool, error) {
+	properties := make(map[string]interface{})
+	required := make(map[string]bool)
+	if schema == nil {
+		return properties, required, nil
+	}
+
+	if members, ok := schema["allOf"].([]interface{}); ok {
+		for _, member := range members {
+			resolved, err := r.resolve(member)
+			if err != nil {
+				return nil, nil, err
+			}
+			memberProps, memberRequired, err := r.objectProperties(resolved)
+			if err != nil {
+				return nil, nil, err
+			}
+			for name, prop := range memberProps {
+				properties[name] = prop
+			}
+			for name := range memberRequired {
+				required[name] = true
+			}
+		}
+	}
+
+	if props, ok := schema["properties"].(map[string]interface{}); ok {
+		for name, prop := range props {
+			properties[name] = prop
+		}
+	}
+	for _, name := range stringSlice(schema["required"]) {
+		required[name] = true
+	}
+
+	return properties, required, nil
+}
+
+// schemaType returns the bridge parameter type for a resolved schema.
+func (r *specResolver) schemaType(schema map[string]interface{}) string {
+	if schema == nil {
+		return "string"
+	}
+
+	switch t := schema["type"].(type) {
+	case string:
+		return schemaTypeToParamType(t)
+	case []interface{}:
+		// OpenAPI 3.1 allows type arrays such as ["string", "null"]
+		for _, v := range t {
+			if s, ok := v.(string); ok && s != "null" {
+				return schemaTypeToParamType(s)
+			}
+		}
+	}
+
+	if _, ok := schema["properties"]; ok {
+		return "object"
+	}
+	if _, ok := schema["items"]; ok {
+		return "array"
+	}
+	if members, ok := schema["allOf"].([]interface{}); ok && len(members) > 0 {
+		if resolved, err := r.resolve(members[0]); err == nil {
+			return r.schemaType(resolved)
+		}
+	}
+
+	return "string"
+}
+
+// resolve follows $ref chains until it reaches a concrete object. It returns
+// nil without error when node is not an object.
+func (r *specResolver) resolve(node interface{}) (map[string]interface{}, error) {
+	seen := make(map[string]bool)
+	for {
+		m, ok := node.(map[string]interface{})
+		if !ok {
+			return nil, nil
+		}
+
+		ref, ok := m["$ref"].(string)
+		if !ok {
+			return m, nil
+		}
+		if seen[ref] {
+			return nil, fmt.Errorf("circular $ref %s", ref)
+		}
+		seen[ref] = true
+
+		target, err := r.lookup(ref)
+		if err != nil {
+			return nil, err
+		}
+		node = target
+	}
+}
+
+// lookup evaluates a local JSON pointer reference such as
+// "#/components/schemas/User".
+func (r *specResolver) lookup(ref string) (interface{}, error) {
+	if !strings.HasPrefix(ref, "#/") {
+		return nil, fmt.Errorf("unsupported $ref %s: only local references are supported", ref)
+	}
+
+	var node interface{} = r.root
+	for _, token := range strings.Split(ref[2:], "/") {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		m, ok := node.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("unresolvable $ref %s", ref)
+		}
+		if node, ok = m[token]; !ok {
+			return nil, fmt.Errorf("unresolvable $ref %s", ref)
+		}
+	}
+
+	return node, nil
+}
+
+func openAPITitle(doc map[string]interface{}) string {
+	info, _ := doc["info"].(map[string]interface{})
+	title, _ := info["title"].(string)
+	return strings.Trim(invalidToolNameChars.ReplaceAllString(title, "_"), "_")
+}
+
+func openAPIServerURL(doc map[string]interface{}) string {
+	servers, _ := doc["servers"].([]interface{})
+	if len(servers) == 0 {
+		return ""
+	}
+	server, _ := servers[0].(map[string]interface{})
+	serverURL, _ := server["url"].(string)
+
+	// Substitute server variables with their defaults
+	variables, _ := server["variables"].(map[string]interface{})
+	for name, raw := range variables {
+		variable, _ := raw.(map[string]interface{})
+		if def, ok := variable["default"].(string); ok {
+			serverURL = strings.ReplaceAll(serverURL, "{"+name+"}", def)
+		}
+	}
+
+	return serverURL
+}
+
+func operationDescription(op map[string]interface{}) string {
+	if summary, ok := op["summary"].(string); ok && summary != "" {
+		return summary
+	}
+	description, _ := op["description"].(string)
+	return strings.TrimSpace(description)
+}
+
+// preferredMediaType picks the JSON media type of a content map, falling back
+// to the first declared type.
+func preferredMediaType(content map[string]interface{}) string {
+	keys := sortedKeys(content)
+	for _, key := range keys {
+		if key == "application/json" {
+			return key
+		}
+	}
+	for _, key := range keys {
+		if strings.Contains(key, "json") {
+			return key
+		}
+	}
+	if len(keys) > 0 {
+		return keys[0]
+	}
+	return ""
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func stringSlice(v interface{}) []string {
+	items, _ := v.([]interface{})
+	result := make([]string, 0, len(items))
+	for _, item := range items {
+		if s, ok := item.(string); ok {
+			result = append(result, s)
+		}
+	}
+	return result
+}
@@ -0,0 +1,452 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+var postmanVariable = regexp.MustCompile(`\{\{\s*([^{}]+?)\s*\}\}`)
+
+// postmanCollection is the subset of the Postman v2.1 collection format
+// needed to derive endpoints.
+type postmanCollection struct {
+	Info struct {
+		Name   string `json:"name"`
+		Schema string `json:"schema"`
+	} `json:"info"`
+	Item     []postmanItem     `json:"item"`
+	Variable []postmanKeyValue `json:"variable"`
+}
+
+type postmanItem struct {
+	Name        string          `json:"name"`
+	Description json.RawMessage `json:"description"`
+	Item        []postmanItem   `json:"item"`
+	Request     *postmanRequest `json:"request"`
+}
+
+type postmanRequest struct {
+	Method      string            `json:"method"`
+	Header      []postmanKeyValue `json:"header"`
+	URL         json.RawMessage   `json:"url"`
+	Body        *postmanBody      `json:"body"`
+	Description json.RawMessage   `json:"description"`
+}
+
+type postmanURL struct {
+	Raw      string            `json:"raw"`
+	Protocol string            `json:"protocol"`
+	Host     json.RawMessage   `json:"host"`
+	Path     json.RawMessage   `json:"path"`
+	Query    []postmanKeyValue `json:"query"`
+	Variable []postmanKeyValue `json:"variable"`
+}
+
+type postmanBody struct {
+	Mode       string            `json:"mode"`
+	Raw        string            `json:"raw"`
+	URLEncoded []postmanKeyValue `json:"urlencoded"`
+	FormData   []postmanKeyValue `json:"formdata"`
+}
+
+type postmanKeyValue struct {
+	Key         string          `json:"key"`
+	Value       interface{}     `json:"value"`
+	Description json.RawMessage `json:"description"`
+	Disabled    bool            `json:"disabled"`
+}
+
+// ImportPostman reads a Postman v2.1 collection and converts its requests
+// into an APIConfig. Folder names prefix tool names and act as tags for
+// filtering, and {{variables}} and :path variables become parameters.
+func ImportPostman(collectionPath string, source *ImportSource) (*APIConfig, error) {
+	data, err := os.ReadFile(collectionPath)
+	if err != nil {
+		return nil, fmt.Errorf("error reading Postman collection: %w", err)
+	}
+
+	var collection postmanCollection
+	if err := json.Unmarshal(data, &collection); err != nil {
+		return nil, fmt.Errorf("error parsing Postman collection %s: %w", collectionPath, err)
+	}
+	if collection.Info.Schema != "" && !strings.Contains(collection.Info.Schema, "v2.") {
+		return nil, fmt.Errorf("%s is not a Postman v2 collection", collectionPath)
+	}
+
+	variables := make(map[string]string)
+	for _, v := range collection.Variable {
+		variables[v.Key] = fmt.Sprintf("%v", valueOrEmpty(v.Value))
+	}
+
+	api := &APIConfig{
+		Name:      strings.Trim(invalidToolNameChars.ReplaceAllString(collection.Info.Name, "_"), "_"),
+		Endpoints: []CustomEndpoint{},
+	}
+
+	var walk func(items []postmanItem, folders []string) error
+	walk = func(items []postmanItem, folders []string) error {
+		for _, item := range items {
+			if item.Request == nil {
+				if err := walk(item.Item, append(folders[:len(folders):len(folders)], item.Name)); err != nil {
+					return err
+				}
+				continue
+			}
+
+			endpoint, origin, err := postmanEndpoint(item, folders, variables)
+			if err != nil {
+				return fmt.Errorf("request %q: %w", item.Name, err)
+			}
+
+			if source != nil && !source.accepts(importedOperation{
+				OperationID: endpoint.Name,
+				Path:        endpoint.Path,
+				Tags:        folders,
+			}) {
+				continue
+			}
+
+			if api.BaseURL == "" && !postmanVariable.MatchString(origin) {
+				api.BaseURL = origin
+			}
+			api.Endpoints = addEndpoint(api.Endpoints, *endpoint)
+		}
+		return nil
+	}
+
+	if err := walk(collection.Item, nil); err != nil {
+		return nil, err
+	}
+
+	return api, nil
+}
+
+func postmanEndpoint(item postmanItem, folders []string, variables map[string]string) (*CustomEndpoint, string, error) {
+	req := item.Request
+	method := strings.ToUpper(req.Method)
+	if method == "" {
+		method = "GET"
+	}
+
+	reqURL, err := parsePostmanURL(req.URL)
+	if err != nil {
+		return nil, "", err
+	}
+
+	nameParts := make([]string, 0, len(folders)+1)
+	for _, folder := range folders {
+		nameParts = append(nameParts, strings.Trim(invalidToolNameChars.ReplaceAllString(folder, "_"), "_"))
+	}
+	nameParts = append(nameParts, strings.Trim(invalidToolNameChars.ReplaceAllString(item.Name, "_"), "_"))
+
+	description := postmanDescription(req.Description)
+	if description == "" {
+		description = postmanDescription(item.Description)
+	}
+	if description == "" {
+		description = item.Name
+	}
+
+	endpoint := &CustomEndpoint{
+		Name:        strings.Join(nameParts, "_"),
+		Description: description,
+		Method:      method,
+		Parameters:  []CustomParameter{},
+		Headers:     map[string]string{},
+	}
+
+	origin, segments := reqURL.split()
+	origin = substitutePostmanVariables(origin, variables)
+
+	addParam := func(param CustomParameter) {
+		endpoint.Parameters = addParameter(endpoint.Parameters, param)
+	}
+
+	pathVariables := make(map[string]postmanKeyValue)
+	for _, v := range reqURL.Variable {
+		pathVariables[v.Key] = v
+	}
+
+	for i, segment := range segments {
+		switch {
+		case strings.HasPrefix(segment, ":"):
+			name := strings.TrimPrefix(segment, ":")
+			segments[i] = "{" + name + "}"
+			v := pathVariables[name]
+			addParam(CustomParameter{
+				Name:        name,
+				Type:        "string",
+				Required:    true,
+				Description: postmanDescription(v.Description),
+				In:          "path",
+			})
+		default:
+			segments[i] = postmanVariable.ReplaceAllStringFunc(segment, func(match string) string {
+				name := postmanVariable.FindStringSubmatch(match)[1]
+				param := CustomParameter{
+					Name:     name,
+					Type:     "string",
+					Required: true,
+					In:       "path",
+				}
+				if def := variables[name]; def != "" {
+					param.Required = false
+					param.Default = def
+				}
+				addParam(param)
+				return "{" + name + "}"
+			})
+		}
+	}
+	endpoint.Path = "/" + strings.Join(segments, "/")
+
+	for _, q := range reqURL.Query {
+		if q.Disabled || q.Key == "" {
+			continue
+		}
+		addParam(postmanParameter(q, "query", variables))
+	}
+
+	for _, h := range req.Header {
+		if h.Disabled || h.Key == "" {
+			continue
+		}
+		value := fmt.Sprintf("%v", valueOrEmpty(h.Value))
+		if postmanVariable.MatchString(value) {
+			addParam(postmanParameter(h, "header", variables))
+			continue
+		}
+		endpoint.Headers[h.Key] = value
+	}
+
+	if req.Body != nil {
+		for _, param := range postmanBodyParameters(req.Body, variables) {
+			addParam(param)
+		}
+	}
+
+	return endpoint, origin, nil
+}
+
+// postmanParameter turns a query, header or form field into a parameter.
+// A literal value becomes the default; a {{variable}} value takes the
+// collection variable's value as default, or is required when it has none.
+func postmanParameter(kv postmanKeyValue, in string, variables map[string]string) CustomParameter {
+	param := CustomParameter{
+		Name:        kv.Key,
+		Type:        "string",
+		Description: postmanDescription(kv.Description),
+		In:          in,
+	}
+
+	value := fmt.Sprintf("%v", valueOrEmpty(kv.Value))
+	if match := postmanVariable.FindStringSubmatch(value); match != nil {
+		if def, ok := variables[match[1]]; ok && def != "" {
+			param.Default = def
+		} else {
+			param.Required = true
+		}
+	} else if value != "" {
+		param.Default = value
+	}
+
+	return param
+}
+
+func postmanBodyParameters(body *postmanBody, variables map[string]string) []CustomParameter {
+	params := []CustomParameter{}
+
+	switch body.Mode {
+	case "raw":
+		var fields map[string]interface{}
+		if err := json.Unmarshal([]byte(quotePostmanVariables(body.Raw)), &fields); err != nil {
+			return params
+		}
+		for _, name := range sortedKeys(fields) {
+			param := CustomParameter{
+				Name: name,
+				Type: jsonValueType(fields[name]),
+				In:   "body",
+			}
+			if s, ok := fields[name].(string); ok {
+				param = postmanParameter(postmanKeyValue{Key: name, Value: s}, "body", variables)
+			} else {
+				param.Default = fields[name]
+			}
+			params = append(params, param)
+		}
+	case "urlencoded", "formdata":
+		fields := body.URLEncoded
+		if body.Mode == "formdata" {
+			fields = body.FormData
+		}
+		for _, field := range fields {
+			if field.Disabled || field.Key == "" {
+				continue
+			}
+			params = append(params, postmanParameter(field, "body", variables))
+		}
+	}
+
+	return params
+}
+
+// quotePostmanVariables wraps bare {{variables}} used as JSON values in
+// quotes so raw bodies such as {"id": {{userId}}} still parse.
+func quotePostmanVariables(raw string) string {
+	var b strings.Builder
+	inString := false
+	escaped := false
+	last := 0
+	for i := 0; i < len(raw); i++ {
+		c := raw[i]
+		switch {
+		case escaped:
+			escaped = false
+		case c == '\\' && inString:
+			escaped = true
+		case c == '"':
+			inString = !inString
+		case !inString && strings.HasPrefix(raw[i:], "{{"):
+			end := strings.Index(raw[i:], "}}")
+			if end < 0 {
+				continue
+			}
+			b.WriteString(raw[last:i])
+			b.WriteString(`"` + raw[i:i+end+2] + `"`)
+			i += end + 1
+			last = i + 1
+		}
+	}
+	b.WriteString(raw[last:])
+	return b.String()
+}
+
+func parsePostmanURL(raw json.RawMessage) (*postmanURL, error) {
+	if len(raw) == 0 {
+		return nil, fmt.Errorf("request has no URL")
+	}
+
+	var rawString string
+	if err := json.Unmarshal(raw, &rawString); err == nil {
+		return &postmanURL{Raw: rawString}, nil
+	}
+
+	var u postmanURL
+	if err := json.Unmarshal(raw, &u); err != nil {
+		return nil, fmt.Errorf("invalid URL: %w", err)
+	}
+	return &u, nil
+}
+
+// split returns the scheme and host portion of the URL and its path
+// segments. Structured host and path fields take precedence over raw.
+func (u *postmanURL) split() (string, []string) {
+	host := joinPostmanParts(u.Host, ".")
+	path := joinPostmanParts(u.Path, "/")
+
+	if host == "" && path == "" {
+		raw := u.Raw
+		if i := strings.IndexAny(raw, "?#"); i >= 0 {
+			raw = raw[:i]
+		}
+		scheme := ""
+		if i := strings.Index(raw, "://"); i >= 0 {
+			scheme, raw = raw[:i], raw[i+3:]
+		}
+		host, path, _ = strings.Cut(raw, "/")
+		if scheme != "" {
+			host = scheme + "://" + host
+		}
+		return host, splitPath(path)
+	}
+
+	if u.Protocol != "" {
+		host = u.Protocol + "://" + host
+	}
+	return host, splitPath(path)
+}
+
+func joinPostmanParts(raw json.RawMessage, sep string) string {
+	if len(raw) == 0 {
+		return ""
+	}
+
+	var parts []string
+	if err := json.Unmarshal(raw, &parts); err == nil {
+		return strings.Join(parts, sep)
+	}
+
+	var single string
+	if err := json.Unmarshal(raw, &single); err == nil {
+		return single
+	}
+
+	return ""
+}
+
+func splitPath(path string) []string {
+	segments := []string{}
+	for _, segment := range strings.Split(path, "/") {
+		if segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+	return segments
+}
+
+func substitutePostmanVariables(s string, variables map[string]string) string {
+	return postmanVariable.ReplaceAllStringFunc(s, func(match string) string {
+		name := postmanVariable.FindStringSubmatch(match)[1]
+		if value, ok := variables[name]; ok && value != "" {
+			return value
+		}
+		return match
+	})
+}
+
+// postmanDescription accepts both the string and {"content": ...} forms.
+func postmanDescription(raw json.RawMessage) string {
+	if len(raw) == 0 {
+		return ""
+	}
+
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return strings.TrimSpace(s)
+	}
+
+	var d struct {
+		Content string `json:"content"`
+	}
+	if err := json.Unmarshal(raw, &d); err == nil {
+		return strings.TrimSpace(d.Content)
+	}
+
+	return ""
+}
+
+func jsonValueType(v interface{}) string {
+	switch v.(type) {
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	default:
+		return "string"
+	}
+}
+
+func valueOrEmpty(v interface{}) interface{} {
+	if v == nil {
+		return ""
+	}
+	return v
+}
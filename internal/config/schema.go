@@ -0,0 +1,175 @@
+package config
+
+import (
+	_ "embed"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// schema is the JSON Schema describing the config file format. Editors pick
+// it up through the "$schema" key of a config file.
+//
+//go:embed config.schema.json
+var schema []byte
+
+// JSONSchema returns the JSON Schema (draft-07) for the config file format
+func JSONSchema() []byte {
+	return append([]byte(nil), schema...)
+}
+
+var validParameterLocations = map[string]bool{
+	"path":   true,
+	"query":  true,
+	"header": true,
+	"body":   true,
+}
+
+var pathPlaceholderPattern = regexp.MustCompile(`\{([^{}]+)\}`)
+
+// checkPathParameters reports {placeholders} in an endpoint's path without a
+// matching path parameter, and path parameters that the path does not use.
+func checkPathParameters(apiName string, endpoint CustomEndpoint) []error {
+	var errs []error
+
+	placeholders := make(map[string]bool)
+	for _, match := range pathPlaceholderPattern.FindAllStringSubmatch(endpoint.Path, -1) {
+		placeholders[match[1]] = true
+	}
+
+	params := make(map[string]bool)
+	for _, param := range endpoint.Parameters {
+		if param.In != "path" {
+			continue
+		}
+		params[param.Name] = true
+		if !placeholders[param.Name] {
+			errs = append(errs, fmt.Errorf("API %s, endpoint %s: path parameter '%s' does not appear in path '%s'", apiName, endpoint.Name, param.Name, endpoint.Path))
+		}
+	}
+
+	for _, match := range pathPlaceholderPattern.FindAllStringSubmatch(endpoint.Path, -1) {
+		if !params[match[1]] {
+			errs = append(errs, fmt.Errorf("API %s, endpoint %s: path placeholder '{%s}' has no matching path parameter", apiName, endpoint.Name, match[1]))
+			params[match[1]] = true
+		}
+	}
+
+	return errs
+}
+
+// unknownFields reports keys in a decoded document that do not correspond to
+// a field of t, such as misspelled or snake_case keys that encoding/json would
+// otherwise silently ignore. A "$schema" key is accepted at the top level of
+// every file.
+func unknownFields(doc interface{}, t reflect.Type, configPath string) []error {
+	if fields, ok := doc.(map[string]interface{}); ok {
+		if _, hasSchema := fields["$schema"]; hasSchema {
+			trimmed := make(map[string]interface{}, len(fields))
+			for key, value := range fields {
+				if key != "$schema" {
+					trimmed[key] = value
+				}
+			}
+			doc = trimmed
+		}
+	}
+
+	var errs []error
+	walkFields(doc, t, "", func(path, suggestion string) {
+		if suggestion != "" {
+			errs = append(errs, fmt.Errorf("%s: %s: unknown field, did you mean '%s'?", configPath, path, suggestion))
+		} else {
+			errs = append(errs, fmt.Errorf("%s: %s: unknown field", configPath, path))
+		}
+	})
+	return errs
+}
+
+func walkFields(doc interface{}, t reflect.Type, path string, report func(path, suggestion string)) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		fields, ok := doc.(map[string]interface{})
+		if !ok {
+			return
+		}
+		known := jsonFields(t)
+		keys := make([]string, 0, len(fields))
+		for key := range fields {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			fieldType, ok := known[key]
+			if !ok {
+				report(joinFieldPath(path, key), suggestField(key, known))
+				continue
+			}
+			walkFields(fields[key], fieldType, joinFieldPath(path, key), report)
+		}
+	case reflect.Slice:
+		items, ok := doc.([]interface{})
+		if !ok {
+			return
+		}
+		for i, item := range items {
+			walkFields(item, t.Elem(), fmt.Sprintf("%s[%d]", path, i), report)
+		}
+	case reflect.Map:
+		fields, ok := doc.(map[string]interface{})
+		if !ok {
+			return
+		}
+		for key, value := range fields {
+			walkFields(value, t.Elem(), joinFieldPath(path, key), report)
+		}
+	}
+}
+
+// jsonFields maps the JSON names of a struct's exported fields to their types
+func jsonFields(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields[name] = field.Type
+	}
+	return fields
+}
+
+// suggestField returns the known field that key most likely meant, ignoring
+// case, underscores and dashes, or "" if there is none.
+func suggestField(key string, known map[string]reflect.Type) string {
+	normalize := func(s string) string {
+		return strings.ToLower(strings.NewReplacer("_", "", "-", "").Replace(s))
+	}
+	want := normalize(key)
+	for name := range known {
+		if normalize(name) == want {
+			return name
+		}
+	}
+	return ""
+}
+
+func joinFieldPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
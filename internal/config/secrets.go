@@ -0,0 +1,141 @@
+package config
+
+import (
+	"io"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// MaskedValue replaces secret values in logs and documentation
+const MaskedValue = "****"
+
+// MinSecretLength is the length below which secrets are not masked: a short
+// value such as "admin" is likely to appear in unrelated output, which
+// masking it would corrupt
+const MinSecretLength = 8
+
+// sensitiveHeaderWords mark header names whose values are treated as secrets
+var sensitiveHeaderWords = []string{"authorization", "cookie", "key", "token", "secret", "password"}
+
+// Secrets returns the values that must not appear in logs or documentation:
+// authentication credentials, values of sensitive-looking headers and values
+// read from ${file:...} references.
+func (c *Config) Secrets() []string {
+	secrets := append([]string{}, c.fileSecrets...)
+	secrets = append(secrets, sensitiveHeaderValues(c.Headers)...)
+
+	for _, api := range c.APIs {
+		secrets = append(secrets, sensitiveHeaderValues(api.Headers)...)
+		for _, endpoint := range api.Endpoints {
+			secrets = append(secrets, sensitiveHeaderValues(endpoint.Headers)...)
+		}
+
+		for _, auth := range api.Auth {
+			if auth.Basic != nil {
+				secrets = append(secrets, auth.Basic.Password)
+			}
+			if auth.Bearer != nil {
+				secrets = append(secrets, auth.Bearer.Token)
+			}
+			if auth.APIKey != nil {
+				secrets = append(secrets, auth.APIKey.Value)
+			}
+			if auth.OAuth2 != nil {
+				secrets = append(secrets, auth.OAuth2.ClientSecret)
+			}
+		}
+	}
+
+	return secrets
+}
+
+// IsSensitiveHeader reports whether the values of a header are treated as
+// secrets, e.g. Authorization, Cookie or X-API-Key
+func IsSensitiveHeader(name string) bool {
+	lower := strings.ToLower(name)
+	for _, word := range sensitiveHeaderWords {
+		if strings.Contains(lower, word) {
+			return true
+		}
+	}
+	return false
+}
+
+func sensitiveHeaderValues(headers map[string]string) []string {
+	var values []string
+	for name, value := range headers {
+		if IsSensitiveHeader(name) {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// Masker replaces secret values with MaskedValue. A nil Masker masks nothing.
+type Masker struct {
+	replacer *strings.Replacer
+}
+
+// NewMasker returns a Masker for the given secrets. Secrets shorter than
+// MinSecretLength are ignored. Besides the secrets themselves, their
+// URL-encoded forms are masked, as they appear in request URLs.
+func NewMasker(secrets []string) *Masker {
+	unique := make(map[string]bool)
+	for _, secret := range secrets {
+		if len(secret) < MinSecretLength {
+			continue
+		}
+		unique[secret] = true
+		unique[url.QueryEscape(secret)] = true
+		unique[url.PathEscape(secret)] = true
+	}
+	if len(unique) == 0 {
+		return &Masker{}
+	}
+
+	// Longer secrets first, so one containing another is masked as a whole
+	sorted := make([]string, 0, len(unique))
+	for secret := range unique {
+		sorted = append(sorted, secret)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if len(sorted[i]) != len(sorted[j]) {
+			return len(sorted[i]) > len(sorted[j])
+		}
+		return sorted[i] < sorted[j]
+	})
+
+	oldnew := make([]string, 0, 2*len(sorted))
+	for _, secret := range sorted {
+		oldnew = append(oldnew, secret, MaskedValue)
+	}
+	return &Masker{replacer: strings.NewReplacer(oldnew...)}
+}
+
+// Mask returns s with every secret replaced
+func (m *Masker) Mask(s string) string {
+	if m == nil || m.replacer == nil {
+		return s
+	}
+	return m.replacer.Replace(s)
+}
+
+// Writer returns a writer that masks secrets before writing to w. Each
+// Write is masked on its own, which suits line-oriented output such as the
+// standard logger.
+func (m *Masker) Writer(w io.Writer) io.Writer {
+	return &maskingWriter{masker: m, w: w}
+}
+
+type maskingWriter struct {
+	masker *Masker
+	w      io.Writer
+}
+
+func (mw *maskingWriter) Write(p []byte) (int, error) {
+	if _, err := io.WriteString(mw.w, mw.masker.Mask(string(p))); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
@@ -0,0 +1,183 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ImportSwagger reads a Swagger 2.0 document and converts its operations
+// into an APIConfig. formData and body parameters become body parameters.
+func ImportSwagger(specPath string, source *ImportSource) (*APIConfig, error) {
+	doc, err := readSpecDocument(specPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if version, _ := doc["swagger"].(string); version != "2.0" {
+		return nil, fmt.Errorf("%s is not a Swagger 2.0 document", specPath)
+	}
+
+	r := &specResolver{root: doc}
+	api := &APIConfig{
+		Name:      openAPITitle(doc),
+		BaseURL:   swaggerBaseURL(doc),
+		Endpoints: []CustomEndpoint{},
+	}
+
+	paths, _ := doc["paths"].(map[string]interface{})
+	for _, apiPath := range sortedKeys(paths) {
+		item, err := r.resolve(paths[apiPath])
+		if err != nil {
+			return nil, fmt.Errorf("path %s: %w", apiPath, err)
+		}
+		if item == nil {
+			continue
+		}
+
+		for _, method := range openAPIMethods {
+			op, ok := item[strings.ToLower(method)].(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			operationID, _ := op["operationId"].(string)
+			if source != nil && !source.accepts(importedOperation{
+				OperationID: operationID,
+				Path:        apiPath,
+				Tags:        stringSlice(op["tags"]),
+			}) {
+				continue
+			}
+
+			endpoint, err := r.swaggerEndpoint(method, apiPath, item, op)
+			if err != nil {
+				return nil, fmt.Errorf("%s %s: %w", method, apiPath, err)
+			}
+			api.Endpoints = addEndpoint(api.Endpoints, *endpoint)
+		}
+	}
+
+	return api, nil
+}
+
+func (r *specResolver) swaggerEndpoint(method, apiPath string, item, op map[string]interface{}) (*CustomEndpoint, error) {
+	operationID, _ := op["operationId"].(string)
+	endpoint := &CustomEndpoint{
+		Name:        toolNameFor(operationID, method, apiPath),
+		Description: operationDescription(op),
+		Method:      method,
+		Path:        apiPath,
+		Parameters:  []CustomParameter{},
+	}
+
+	var rawParams []interface{}
+	if params, ok := item["parameters"].([]interface{}); ok {
+		rawParams = append(rawParams, params...)
+	}
+	if params, ok := op["parameters"].([]interface{}); ok {
+		rawParams = append(rawParams, params...)
+	}
+
+	index := make(map[string]int)
+	for _, raw := range rawParams {
+		params, err := r.swaggerParameters(raw)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, param := range params {
+			key := param.In + ":" + param.Name
+			if i, exists := index[key]; exists {
+				endpoint.Parameters[i] = param
+				continue
+			}
+			n := len(endpoint.Parameters)
+			endpoint.Parameters = addParameter(endpoint.Parameters, param)
+			if len(endpoint.Parameters) > n {
+				index[key] = n
+			}
+		}
+	}
+
+	response, err := r.successResponse(op["responses"])
+	if err != nil {
+		return nil, fmt.Errorf("responses: %w", err)
+	}
+	if response != nil {
+		if endpoint.OutputSchema, err = r.inlineSchema(response["schema"]); err != nil {
+			return nil, fmt.Errorf("responses: %w", err)
+		}
+	}
+
+	return endpoint, nil
+}
+
+// swaggerParameters converts a single Swagger parameter. A body parameter
+// with an object schema expands into one parameter per property.
+func (r *specResolver) swaggerParameters(raw interface{}) ([]CustomParameter, error) {
+	p, err := r.resolve(raw)
+	if err != nil {
+		return nil, err
+	}
+	if p == nil {
+		return nil, nil
+	}
+
+	name, _ := p["name"].(string)
+	in, _ := p["in"].(string)
+	if name == "" {
+		return nil, fmt.Errorf("parameter without a name")
+	}
+
+	required, _ := p["required"].(bool)
+	description, _ := p["description"].(string)
+
+	switch in {
+	case "path", "query", "header", "formData":
+		param := CustomParameter{
+			Name:        name,
+			Type:        r.schemaType(p),
+			Required:    required || in == "path",
+			Description: description,
+			Default:     p["default"],
+			In:          in,
+		}
+		if in == "formData" {
+			param.In = "body"
+		}
+		return []CustomParameter{param}, nil
+
+	case "body":
+		schema, err := r.resolve(p["schema"])
+		if err != nil {
+			return nil, fmt.Errorf("parameter %s: %w", name, err)
+		}
+		if r.schemaType(schema) == "object" {
+			return r.schemaBodyParameters(schema)
+		}
+		return []CustomParameter{{
+			Name:        name,
+			Type:        r.schemaType(schema),
+			Required:    required,
+			Description: description,
+			In:          "body",
+		}}, nil
+	}
+
+	return nil, nil
+}
+
+func swaggerBaseURL(doc map[string]interface{}) string {
+	host, _ := doc["host"].(string)
+	if host == "" {
+		return ""
+	}
+
+	scheme := "https"
+	if schemes := stringSlice(doc["schemes"]); len(schemes) > 0 {
+		scheme = schemes[0]
+	}
+
+	basePath, _ := doc["basePath"].(string)
+	return scheme + "://" + host + strings.TrimSuffix(basePath, "/")
+}
@@ -0,0 +1,198 @@
+// Package jsonpath evaluates a subset of JSONPath against decoded JSON
+// values: member access (.name or ['name']), array indexes ([0], [-1]) and
+// wildcards ([*] or .*). The leading $ is optional, so "data.items[*].id"
+// and "$.data.items[*].id" are the same path.
+package jsonpath
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type stepKind int
+
+const (
+	memberStep stepKind = iota
+	indexStep
+	wildcardStep
+)
+
+type step struct {
+	kind  stepKind
+	name  string
+	index int
+}
+
+// Path is a compiled path expression
+type Path struct {
+	expr  string
+	steps []step
+	multi bool
+}
+
+// Compile parses a path expression
+func Compile(expr string) (*Path, error) {
+	p := &Path{expr: expr}
+	s := strings.TrimSpace(expr)
+	if strings.HasPrefix(s, "$") {
+		s = s[1:]
+	} else if s != "" && s[0] != '.' && s[0] != '[' {
+		s = "." + s
+	}
+
+	for i := 0; i < len(s); {
+		switch s[i] {
+		case '.':
+			i++
+			if i < len(s) && s[i] == '*' {
+				p.steps = append(p.steps, step{kind: wildcardStep})
+				i++
+				continue
+			}
+			end := i
+			for end < len(s) && s[end] != '.' && s[end] != '[' {
+				end++
+			}
+			if end == i {
+				return nil, fmt.Errorf("invalid path %q: missing member name at offset %d", expr, i)
+			}
+			p.steps = append(p.steps, step{kind: memberStep, name: s[i:end]})
+			i = end
+
+		case '[':
+			end := strings.IndexByte(s[i:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("invalid path %q: unclosed '[' at offset %d", expr, i)
+			}
+			inner := strings.TrimSpace(s[i+1 : i+end])
+			switch {
+			case inner == "*":
+				p.steps = append(p.steps, step{kind: wildcardStep})
+			case len(inner) >= 2 && (inner[0] == '\'' || inner[0] == '"') && inner[len(inner)-1] == inner[0]:
+				p.steps = append(p.steps, step{kind: memberStep, name: inner[1 : len(inner)-1]})
+			default:
+				index, err := strconv.Atoi(inner)
+				if err != nil {
+					return nil, fmt.Errorf("invalid path %q: %q is not an index, a quoted name or '*'", expr, inner)
+				}
+				p.steps = append(p.steps, step{kind: indexStep, index: index})
+			}
+			i += end + 1
+
+		default:
+			return nil, fmt.Errorf("invalid path %q: unexpected %q at offset %d", expr, s[i], i)
+		}
+	}
+
+	for _, st := range p.steps {
+		if st.kind == wildcardStep {
+			p.multi = true
+		}
+	}
+	return p, nil
+}
+
+// String returns the expression the path was compiled from
+func (p *Path) String() string {
+	return p.expr
+}
+
+// Get returns the value at the path in data, or nil if there is none. A path
+// with a wildcard returns an array of all matching values.
+func (p *Path) Get(data interface{}) interface{} {
+	nodes := []interface{}{data}
+	for _, st := range p.steps {
+		var next []interface{}
+		for _, node := range nodes {
+			next = append(next, st.apply(node)...)
+		}
+		nodes = next
+	}
+
+	if p.multi {
+		if nodes == nil {
+			return []interface{}{}
+		}
+		return nodes
+	}
+	if len(nodes) == 0 {
+		return nil
+	}
+	return nodes[0]
+}
+
+// Schema returns the JSON schema of what Get returns for documents described
+// by schema, or nil if schema does not describe the values at the path
+func (p *Path) Schema(schema map[string]interface{}) map[string]interface{} {
+	for _, st := range p.steps {
+		if schema = st.schema(schema); schema == nil {
+			return nil
+		}
+	}
+	if p.multi {
+		return map[string]interface{}{"type": "array", "items": schema}
+	}
+	return schema
+}
+
+// schema returns the schema of the values the step selects in values
+// described by schema
+func (st step) schema(schema map[string]interface{}) map[string]interface{} {
+	var sub interface{}
+	switch st.kind {
+	case memberStep:
+		if properties, ok := schema["properties"].(map[string]interface{}); ok {
+			sub = properties[st.name]
+		}
+	case indexStep:
+		sub = schema["items"]
+	case wildcardStep:
+		if schema["type"] == "object" {
+			sub = schema["additionalProperties"]
+		} else {
+			sub = schema["items"]
+		}
+	}
+	result, _ := sub.(map[string]interface{})
+	return result
+}
+
+func (st step) apply(node interface{}) []interface{} {
+	switch st.kind {
+	case memberStep:
+		if object, ok := node.(map[string]interface{}); ok {
+			if value, ok := object[st.name]; ok {
+				return []interface{}{value}
+			}
+		}
+	case indexStep:
+		if array, ok := node.([]interface{}); ok {
+			index := st.index
+			if index < 0 {
+				index += len(array)
+			}
+			if index >= 0 && index < len(array) {
+				return []interface{}{array[index]}
+			}
+		}
+	case wildcardStep:
+		switch v := node.(type) {
+		case []interface{}:
+			return v
+		case map[string]interface{}:
+			keys := make([]string, 0, len(v))
+			for key := range v {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			values := make([]interface{}, len(keys))
+			for i, key := range keys {
+				values[i] = v[key]
+			}
+			return values
+		}
+	}
+	return nil
+}
@@ -1,15 +1,23 @@
 package mcp
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
+	"sync"
+	"time"
 
 	"mcp-bridge/internal/transport"
 	"mcp-bridge/pkg/types"
 )
 
+// DefaultMaxConcurrency is the number of requests handled in parallel when
+// no limit is configured
+const DefaultMaxConcurrency = 8
+
 type Server struct {
 	capabilities    types.ServerCapabilities
 	tools           []types.Tool
@@ -17,9 +25,15 @@ type Server struct {
 	prompts         []types.Prompt
 	transport       transport.Transport
 	initialized     bool
-	toolHandler     func(string, map[string]interface{}) (*types.CallToolResult, error)
-	resourceHandler func(string) (*types.ReadResourceResult, error)
-	promptHandler   func(string, map[string]interface{}) (*types.GetPromptResult, error)
+	toolHandler     func(context.Context, string, map[string]interface{}) (*types.CallToolResult, error)
+	resourceHandler func(context.Context, string) (*types.ReadResourceResult, error)
+	promptHandler   func(context.Context, string, map[string]interface{}) (*types.GetPromptResult, error)
+	maxConcurrency  int
+	requestTimeout  time.Duration
+	mu              sync.RWMutex                  // guards tools, resources, prompts, handlers and initialized
+	writeMu         sync.Mutex                    // serializes writes to the transport
+	cancels         map[string]context.CancelFunc // in-flight requests by ID, guarded by cancelMu
+	cancelMu        sync.Mutex
 }
 
 func NewServer(t transport.Transport) *Server {
@@ -34,18 +48,53 @@ func NewServer(t transport.Transport) *Server {
 			},
 			Logging: &types.LoggingCapability{},
 		},
-		tools:     []types.Tool{},
-		resources: []types.Resource{},
-		prompts:   []types.Prompt{},
-		transport: t,
+		tools:          []types.Tool{},
+		resources:      []types.Resource{},
+		prompts:        []types.Prompt{},
+		transport:      t,
+		maxConcurrency: DefaultMaxConcurrency,
+		cancels:        make(map[string]context.CancelFunc),
+	}
+}
+
+// SetMaxConcurrency limits how many requests are handled in parallel. It
+// must be called before Start; values below 1 restore the default.
+func (s *Server) SetMaxConcurrency(n int) {
+	if n < 1 {
+		n = DefaultMaxConcurrency
 	}
+	s.maxConcurrency = n
+}
+
+// SetRequestTimeout sets the deadline applied to each tools/call,
+// resources/read and prompts/get request. Zero disables the deadline.
+func (s *Server) SetRequestTimeout(timeout time.Duration) {
+	s.requestTimeout = timeout
 }
 
+// Start reads messages until the transport reaches EOF. Requests that call
+// into handlers (tools/call, resources/read, prompts/get) run on up to
+// maxConcurrency workers, so a slow upstream call does not hold up other
+// requests. All other messages, including initialize and notifications, are
+// handled in arrival order by the read loop. Every request receives exactly
+// one response, identified by its ID, unless the client cancels it with
+// notifications/cancelled. Start waits for in-flight requests to finish
+// before returning.
 func (s *Server) Start() error {
 	if err := s.transport.Start(); err != nil {
 		return fmt.Errorf("error starting transport: %w", err)
 	}
 
+	queue := newRequestQueue()
+	var workers sync.WaitGroup
+	for i := 0; i < s.maxConcurrency; i++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			s.work(queue)
+		}()
+	}
+
 	for {
 		msg, err := s.transport.ReadMessage()
 		if err != nil {
@@ -56,38 +105,166 @@ func (s *Server) Start() error {
 			continue
 		}
 
-		if msg == nil {
-			continue // No message available (for HTTP transport polling)
+		if isConcurrentMethod(msg.Method) {
+			s.dispatch(msg, queue)
+			continue
 		}
 
-		if err := s.handleMessage(msg); err != nil {
+		if err := s.handleMessage(context.Background(), msg); err != nil {
 			log.Printf("Error handling message: %v", err)
 		}
 	}
 
+	queue.close()
+	workers.Wait()
 	return nil
 }
 
-func (s *Server) handleMessage(msg *types.JSONRPCMessage) error {
+// isConcurrentMethod reports whether a method may block on a handler and is
+// therefore handled off the read loop
+func isConcurrentMethod(method string) bool {
+	switch method {
+	case "tools/call", "resources/read", "prompts/get":
+		return true
+	default:
+		return false
+	}
+}
+
+// dispatch queues a message for the workers. The request is registered for
+// cancellation before it is queued so that a notifications/cancelled
+// arriving while it waits for a worker is honored.
+func (s *Server) dispatch(msg *types.JSONRPCMessage, queue *requestQueue) {
+	ctx, finish := s.beginRequest(msg.ID)
+	queue.push(queuedRequest{ctx: ctx, finish: finish, msg: msg})
+}
+
+// work handles queued requests until the queue is closed and drained
+func (s *Server) work(queue *requestQueue) {
+	for {
+		req, ok := queue.pop()
+		if !ok {
+			return
+		}
+		if !isCancelled(req.ctx) {
+			if err := s.handleMessage(req.ctx, req.msg); err != nil {
+				log.Printf("Error handling message: %v", err)
+			}
+		}
+		req.finish()
+	}
+}
+
+type queuedRequest struct {
+	ctx    context.Context
+	finish func()
+	msg    *types.JSONRPCMessage
+}
+
+// requestQueue holds requests waiting for a worker. It does not block the
+// read loop, which keeps reading cancellations and other messages while all
+// workers are busy.
+type requestQueue struct {
+	mu       sync.Mutex
+	ready    *sync.Cond
+	requests []queuedRequest
+	closed   bool
+}
+
+func newRequestQueue() *requestQueue {
+	q := &requestQueue{}
+	q.ready = sync.NewCond(&q.mu)
+	return q
+}
+
+func (q *requestQueue) push(req queuedRequest) {
+	q.mu.Lock()
+	q.requests = append(q.requests, req)
+	q.mu.Unlock()
+	q.ready.Signal()
+}
+
+// close makes pop return false once the queued requests are taken
+func (q *requestQueue) close() {
+	q.mu.Lock()
+	q.closed = true
+	q.mu.Unlock()
+	q.ready.Broadcast()
+}
+
+// pop waits for the next request; ok is false when the queue is closed and
+// empty
+func (q *requestQueue) pop() (req queuedRequest, ok bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for len(q.requests) == 0 && !q.closed {
+		q.ready.Wait()
+	}
+	if len(q.requests) == 0 {
+		return queuedRequest{}, false
+	}
+	req = q.requests[0]
+	q.requests[0] = queuedRequest{}
+	q.requests = q.requests[1:]
+	return req, true
+}
+
+// beginRequest creates the context for a request, applying the request
+// timeout and registering it so the client can cancel it
+func (s *Server) beginRequest(id interface{}) (context.Context, func()) {
+	var ctx context.Context
+	var cancel context.CancelFunc
+	if s.requestTimeout > 0 {
+		ctx, cancel = context.WithTimeout(context.Background(), s.requestTimeout)
+	} else {
+		ctx, cancel = context.WithCancel(context.Background())
+	}
+
+	if id == nil {
+		return ctx, cancel
+	}
+
+	key := fmt.Sprint(id)
+	s.cancelMu.Lock()
+	s.cancels[key] = cancel
+	s.cancelMu.Unlock()
+
+	return ctx, func() {
+		s.cancelMu.Lock()
+		delete(s.cancels, key)
+		s.cancelMu.Unlock()
+		cancel()
+	}
+}
+
+// isCancelled reports whether a request was cancelled by the client, in
+// which case no response is sent
+func isCancelled(ctx context.Context) bool {
+	return errors.Is(ctx.Err(), context.Canceled)
+}
+
+func (s *Server) handleMessage(ctx context.Context, msg *types.JSONRPCMessage) error {
 	switch msg.Method {
 	case "initialize":
 		return s.handleInitialize(msg)
-	case "initialized":
+	case "initialized", "notifications/initialized":
 		return s.handleInitialized(msg)
 	case "tools/list":
 		return s.handleToolsList(msg)
 	case "tools/call":
-		return s.handleToolsCall(msg)
+		return s.handleToolsCall(ctx, msg)
 	case "resources/list":
 		return s.handleResourcesList(msg)
 	case "resources/read":
-		return s.handleResourcesRead(msg)
+		return s.handleResourcesRead(ctx, msg)
 	case "prompts/list":
 		return s.handlePromptsList(msg)
 	case "prompts/get":
-		return s.handlePromptsGet(msg)
+		return s.handlePromptsGet(ctx, msg)
 	case "ping":
 		return s.handlePing(msg)
+	case "notifications/cancelled":
+		return s.handleCancelled(msg)
 	default:
 		if msg.ID != nil {
 			s.sendError(msg.ID, -32601, "Method not found", nil)
@@ -114,8 +291,14 @@ func (s *Server) handleInitialize(msg *types.JSONRPCMessage) error {
 		}
 	}
 
+	// Echo the requested protocol version when supported, otherwise offer the latest
+	protocolVersion := params.ProtocolVersion
+	if !types.IsSupportedProtocolVersion(protocolVersion) {
+		protocolVersion = types.LatestProtocolVersion
+	}
+
 	result := types.InitializeResult{
-		ProtocolVersion: "2024-11-05",
+		ProtocolVersion: protocolVersion,
 		Capabilities:    s.capabilities,
 		ServerInfo: types.ServerInfo{
 			Name:    "mcp-bridge",
@@ -127,7 +310,9 @@ func (s *Server) handleInitialize(msg *types.JSONRPCMessage) error {
 }
 
 func (s *Server) handleInitialized(_ *types.JSONRPCMessage) error {
+	s.mu.Lock()
 	s.initialized = true
+	s.mu.Unlock()
 	return nil
 }
 
@@ -136,14 +321,16 @@ func (s *Server) handleToolsList(msg *types.JSONRPCMessage) error {
 		return fmt.Errorf("tools/list request must have an ID")
 	}
 
+	s.mu.RLock()
 	result := types.ToolsListResult{
-		Tools: s.tools,
+		Tools: append([]types.Tool{}, s.tools...),
 	}
+	s.mu.RUnlock()
 
 	return s.sendResult(msg.ID, result)
 }
 
-func (s *Server) handleToolsCall(msg *types.JSONRPCMessage) error {
+func (s *Server) handleToolsCall(ctx context.Context, msg *types.JSONRPCMessage) error {
 	if msg.ID == nil {
 		return fmt.Errorf("tools/call request must have an ID")
 	}
@@ -161,7 +348,10 @@ func (s *Server) handleToolsCall(msg *types.JSONRPCMessage) error {
 		}
 	}
 
-	result, err := s.callTool(params.Name, params.Arguments)
+	result, err := s.callTool(ctx, params.Name, params.Arguments)
+	if isCancelled(ctx) {
+		return nil
+	}
 	if err != nil {
 		s.sendError(msg.ID, -32603, "Internal error", err)
 		return nil
@@ -175,14 +365,16 @@ func (s *Server) handleResourcesList(msg *types.JSONRPCMessage) error {
 		return fmt.Errorf("resources/list request must have an ID")
 	}
 
+	s.mu.RLock()
 	result := types.ResourcesListResult{
-		Resources: s.resources,
+		Resources: append([]types.Resource{}, s.resources...),
 	}
+	s.mu.RUnlock()
 
 	return s.sendResult(msg.ID, result)
 }
 
-func (s *Server) handleResourcesRead(msg *types.JSONRPCMessage) error {
+func (s *Server) handleResourcesRead(ctx context.Context, msg *types.JSONRPCMessage) error {
 	if msg.ID == nil {
 		return fmt.Errorf("resources/read request must have an ID")
 	}
@@ -200,7 +392,10 @@ func (s *Server) handleResourcesRead(msg *types.JSONRPCMessage) error {
 		}
 	}
 
-	result, err := s.readResource(params.URI)
+	result, err := s.readResource(ctx, params.URI)
+	if isCancelled(ctx) {
+		return nil
+	}
 	if err != nil {
 		s.sendError(msg.ID, -32603, "Internal error", err)
 		return nil
@@ -214,14 +409,16 @@ func (s *Server) handlePromptsList(msg *types.JSONRPCMessage) error {
 		return fmt.Errorf("prompts/list request must have an ID")
 	}
 
+	s.mu.RLock()
 	result := types.PromptsListResult{
-		Prompts: s.prompts,
+		Prompts: append([]types.Prompt{}, s.prompts...),
 	}
+	s.mu.RUnlock()
 
 	return s.sendResult(msg.ID, result)
 }
 
-func (s *Server) handlePromptsGet(msg *types.JSONRPCMessage) error {
+func (s *Server) handlePromptsGet(ctx context.Context, msg *types.JSONRPCMessage) error {
 	if msg.ID == nil {
 		return fmt.Errorf("prompts/get request must have an ID")
 	}
@@ -239,7 +436,10 @@ func (s *Server) handlePromptsGet(msg *types.JSONRPCMessage) error {
 		}
 	}
 
-	result, err := s.getPrompt(params.Name, params.Arguments)
+	result, err := s.getPrompt(ctx, params.Name, params.Arguments)
+	if isCancelled(ctx) {
+		return nil
+	}
 	if err != nil {
 		s.sendError(msg.ID, -32603, "Internal error", err)
 		return nil
@@ -256,6 +456,34 @@ func (s *Server) handlePing(msg *types.JSONRPCMessage) error {
 	return s.sendResult(msg.ID, map[string]interface{}{})
 }
 
+// handleCancelled aborts an in-flight request named by the client
+func (s *Server) handleCancelled(msg *types.JSONRPCMessage) error {
+	var params types.CancelledParams
+	if msg.Params != nil {
+		paramsBytes, err := json.Marshal(msg.Params)
+		if err != nil {
+			return fmt.Errorf("invalid cancellation params: %w", err)
+		}
+		if err := json.Unmarshal(paramsBytes, &params); err != nil {
+			return fmt.Errorf("invalid cancellation params: %w", err)
+		}
+	}
+
+	if params.RequestID == nil {
+		return nil
+	}
+
+	s.cancelMu.Lock()
+	cancel, ok := s.cancels[fmt.Sprint(params.RequestID)]
+	s.cancelMu.Unlock()
+
+	// Unknown or already finished requests are ignored, as the spec requires
+	if ok {
+		cancel()
+	}
+	return nil
+}
+
 func (s *Server) sendResult(id interface{}, result interface{}) error {
 	response := types.JSONRPCMessage{
 		JSONRpc: "2.0",
@@ -279,36 +507,79 @@ func (s *Server) sendError(id interface{}, code int, message string, data interf
 }
 
 func (s *Server) sendMessage(msg types.JSONRPCMessage) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
 	return s.transport.WriteMessage(&msg)
 }
 
 func (s *Server) AddTool(tool types.Tool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	s.tools = append(s.tools, tool)
 }
 
+// SetTools replaces the tool list. Clients see either the old or the new
+// list as a whole; call NotifyToolsListChanged to tell them to refetch it.
+func (s *Server) SetTools(tools []types.Tool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tools = append([]types.Tool{}, tools...)
+}
+
+// NotifyToolsListChanged sends notifications/tools/list_changed once a
+// client has completed initialization. Before that the client has not
+// listed the tools yet, so there is nothing to invalidate.
+func (s *Server) NotifyToolsListChanged() error {
+	s.mu.RLock()
+	initialized := s.initialized
+	s.mu.RUnlock()
+
+	if !initialized {
+		return nil
+	}
+	return s.sendMessage(types.JSONRPCMessage{
+		JSONRpc: "2.0",
+		Method:  "notifications/tools/list_changed",
+	})
+}
+
 func (s *Server) AddResource(resource types.Resource) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	s.resources = append(s.resources, resource)
 }
 
 func (s *Server) AddPrompt(prompt types.Prompt) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	s.prompts = append(s.prompts, prompt)
 }
 
-func (s *Server) SetToolHandler(handler func(name string, args map[string]interface{}) (*types.CallToolResult, error)) {
+func (s *Server) SetToolHandler(handler func(ctx context.Context, name string, args map[string]interface{}) (*types.CallToolResult, error)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	s.toolHandler = handler
 }
 
-func (s *Server) SetResourceHandler(handler func(uri string) (*types.ReadResourceResult, error)) {
+func (s *Server) SetResourceHandler(handler func(ctx context.Context, uri string) (*types.ReadResourceResult, error)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	s.resourceHandler = handler
 }
 
-func (s *Server) SetPromptHandler(handler func(name string, args map[string]interface{}) (*types.GetPromptResult, error)) {
+func (s *Server) SetPromptHandler(handler func(ctx context.Context, name string, args map[string]interface{}) (*types.GetPromptResult, error)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	s.promptHandler = handler
 }
 
-func (s *Server) callTool(name string, args map[string]interface{}) (*types.CallToolResult, error) {
-	if s.toolHandler != nil {
-		return s.toolHandler(name, args)
+func (s *Server) callTool(ctx context.Context, name string, args map[string]interface{}) (*types.CallToolResult, error) {
+	s.mu.RLock()
+	handler := s.toolHandler
+	s.mu.RUnlock()
+
+	if handler != nil {
+		return handler(ctx, name, args)
 	}
 
 	return &types.CallToolResult{
@@ -322,9 +593,13 @@ func (s *Server) callTool(name string, args map[string]interface{}) (*types.Call
 	}, nil
 }
 
-func (s *Server) readResource(uri string) (*types.ReadResourceResult, error) {
-	if s.resourceHandler != nil {
-		return s.resourceHandler(uri)
+func (s *Server) readResource(ctx context.Context, uri string) (*types.ReadResourceResult, error) {
+	s.mu.RLock()
+	handler := s.resourceHandler
+	s.mu.RUnlock()
+
+	if handler != nil {
+		return handler(ctx, uri)
 	}
 
 	return &types.ReadResourceResult{
@@ -338,9 +613,13 @@ func (s *Server) readResource(uri string) (*types.ReadResourceResult, error) {
 	}, nil
 }
 
-func (s *Server) getPrompt(name string, args map[string]interface{}) (*types.GetPromptResult, error) {
-	if s.promptHandler != nil {
-		return s.promptHandler(name, args)
+func (s *Server) getPrompt(ctx context.Context, name string, args map[string]interface{}) (*types.GetPromptResult, error) {
+	s.mu.RLock()
+	handler := s.promptHandler
+	s.mu.RUnlock()
+
+	if handler != nil {
+		return handler(ctx, name, args)
 	}
 
 	return &types.GetPromptResult{
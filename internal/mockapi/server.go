@@ -0,0 +1,509 @@
+// Package mockapi serves a configurable REST API with in-memory resources,
+// used to try out and test the bridge without a real upstream service.
+package mockapi
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type Config struct {
+	Server    ServerConfig     `json:"server"`
+	Auth      AuthConfig       `json:"auth"`
+	Resources []ResourceConfig `json:"resources"`
+	Endpoints []EndpointConfig `json:"endpoints"`
+}
+
+type ServerConfig struct {
+	Port string `json:"port"`
+	Name string `json:"name"`
+}
+
+// AuthConfig protects the mock API with Basic auth, or with OAuth2 bearer
+// tokens when Type is "oauth2". In OAuth2 mode Username and Password are the
+// client ID and secret accepted by the client credentials token endpoint.
+type AuthConfig struct {
+	Enabled   bool   `json:"enabled"`
+	Type      string `json:"type,omitempty"`
+	Username  string `json:"username"`
+	Password  string `json:"password"`
+	TokenPath string `json:"tokenPath,omitempty"`
+	TokenTTL  int    `json:"tokenTtl,omitempty"`
+}
+
+type ResourceConfig struct {
+	Name       string                   `json:"name"`
+	Path       string                   `json:"path"`
+	Enabled    bool                     `json:"enabled"`
+	Data       []map[string]interface{} `json:"data"`
+	Methods    []string                 `json:"methods"`
+	SupportsID bool                     `json:"supportsId"`
+}
+
+type EndpointConfig struct {
+	Path     string                 `json:"path"`
+	Method   string                 `json:"method"`
+	Enabled  bool                   `json:"enabled"`
+	Response map[string]interface{} `json:"response"`
+}
+
+// DefaultConfigPath is the mock configuration used when none is given
+const DefaultConfigPath = "configs/mock/users.json"
+
+// Server is a mock REST API described by a Config
+type Server struct {
+	config *Config
+
+	// issuedTokens maps OAuth2 access tokens to their expiry
+	issuedTokens   map[string]time.Time
+	issuedTokensMu sync.Mutex
+}
+
+// LoadConfig reads a mock API configuration, DefaultConfigPath if
+// configPath is empty
+func LoadConfig(configPath string) (*Config, error) {
+	if configPath == "" {
+		configPath = DefaultConfigPath
+	}
+
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("error reading config file: %w", err)
+	}
+
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("error parsing config file: %w", err)
+	}
+
+	return &cfg, nil
+}
+
+// ApplyEnv overrides the port and authentication settings of cfg from the
+// PORT, AUTH_ENABLED, AUTH_TYPE, AUTH_USERNAME and AUTH_PASSWORD environment
+// variables
+func ApplyEnv(cfg *Config) {
+	if port := os.Getenv("PORT"); port != "" {
+		cfg.Server.Port = port
+	}
+
+	if os.Getenv("AUTH_ENABLED") == "true" {
+		cfg.Auth.Enabled = true
+		if authType := os.Getenv("AUTH_TYPE"); authType != "" {
+			cfg.Auth.Type = authType
+		}
+		if username := os.Getenv("AUTH_USERNAME"); username != "" {
+			cfg.Auth.Username = username
+		}
+		if password := os.Getenv("AUTH_PASSWORD"); password != "" {
+			cfg.Auth.Password = password
+		}
+	}
+}
+
+// NewServer returns a mock API serving cfg
+func NewServer(cfg *Config) *Server {
+	if cfg.Auth.Enabled && cfg.Auth.Type == "oauth2" && cfg.Auth.TokenPath == "" {
+		cfg.Auth.TokenPath = "/oauth/token"
+	}
+	return &Server{
+		config:       cfg,
+		issuedTokens: make(map[string]time.Time),
+	}
+}
+
+func (s *Server) oauth2Enabled() bool {
+	return s.config.Auth.Enabled && s.config.Auth.Type == "oauth2"
+}
+
+func (s *Server) basicAuthMiddleware(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !s.config.Auth.Enabled {
+			next(w, r)
+			return
+		}
+
+		auth := r.Header.Get("Authorization")
+		if auth == "" {
+			w.Header().Set("WWW-Authenticate", `Basic realm="Mock API"`)
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		if !strings.HasPrefix(auth, "Basic ") {
+			http.Error(w, "Invalid authorization header", http.StatusUnauthorized)
+			return
+		}
+
+		encoded := strings.TrimPrefix(auth, "Basic ")
+		decoded, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			http.Error(w, "Invalid base64 encoding", http.StatusUnauthorized)
+			return
+		}
+
+		creds := strings.SplitN(string(decoded), ":", 2)
+		if len(creds) != 2 {
+			http.Error(w, "Invalid credentials format", http.StatusUnauthorized)
+			return
+		}
+
+		username, password := creds[0], creds[1]
+		if username != s.config.Auth.Username || password != s.config.Auth.Password {
+			http.Error(w, "Invalid credentials", http.StatusUnauthorized)
+			return
+		}
+
+		next(w, r)
+	}
+}
+
+// tokenHandler implements the OAuth2 client credentials grant, accepting the
+// client credentials either as HTTP Basic auth or as form parameters.
+func (s *Server) tokenHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method != "POST" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		writeTokenError(w, http.StatusBadRequest, "invalid_request")
+		return
+	}
+	if r.PostForm.Get("grant_type") != "client_credentials" {
+		writeTokenError(w, http.StatusBadRequest, "unsupported_grant_type")
+		return
+	}
+
+	clientID, clientSecret, ok := r.BasicAuth()
+	if !ok {
+		clientID, clientSecret = r.PostForm.Get("client_id"), r.PostForm.Get("client_secret")
+	}
+	if clientID != s.config.Auth.Username || clientSecret != s.config.Auth.Password {
+		writeTokenError(w, http.StatusUnauthorized, "invalid_client")
+		return
+	}
+
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		http.Error(w, "Error generating token", http.StatusInternalServerError)
+		return
+	}
+	token := hex.EncodeToString(buf)
+
+	ttl := s.config.Auth.TokenTTL
+	if ttl <= 0 {
+		ttl = 3600
+	}
+
+	s.issuedTokensMu.Lock()
+	s.issuedTokens[token] = time.Now().Add(time.Duration(ttl) * time.Second)
+	s.issuedTokensMu.Unlock()
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"access_token": token,
+		"token_type":   "Bearer",
+		"expires_in":   ttl,
+		"scope":        r.PostForm.Get("scope"),
+	})
+}
+
+func writeTokenError(w http.ResponseWriter, status int, code string) {
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": code})
+}
+
+func (s *Server) bearerAuthMiddleware(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		auth := r.Header.Get("Authorization")
+		if !strings.HasPrefix(auth, "Bearer ") {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="Mock API"`)
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		s.issuedTokensMu.Lock()
+		expiry, ok := s.issuedTokens[strings.TrimPrefix(auth, "Bearer ")]
+		s.issuedTokensMu.Unlock()
+
+		if !ok || time.Now().After(expiry) {
+			w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+			http.Error(w, "Invalid or expired token", http.StatusUnauthorized)
+			return
+		}
+
+		next(w, r)
+	}
+}
+
+func logHeadersMiddleware(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		log.Printf("[%s] %s %s", r.Method, r.URL.Path, r.RemoteAddr)
+		log.Printf("Request Headers:")
+		for name, values := range r.Header {
+			for _, value := range values {
+				log.Printf("  %s: %s", name, value)
+			}
+		}
+		next(w, r)
+	}
+}
+
+func corsHandler(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-API-Key")
+
+		if r.Method == "OPTIONS" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+
+		next(w, r)
+	}
+}
+
+func createResourceHandler(resource *ResourceConfig) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		if !contains(resource.Methods, r.Method) {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		switch r.Method {
+		case "GET":
+			if err := json.NewEncoder(w).Encode(resource.Data); err != nil {
+				http.Error(w, "Error encoding data", http.StatusInternalServerError)
+			}
+		case "POST":
+			var newItem map[string]interface{}
+			if err := json.NewDecoder(r.Body).Decode(&newItem); err != nil {
+				http.Error(w, "Invalid JSON", http.StatusBadRequest)
+				return
+			}
+
+			nextID := len(resource.Data) + 1
+			newItem["id"] = nextID
+			newItem["created"] = time.Now().Format(time.RFC3339)
+			resource.Data = append(resource.Data, newItem)
+
+			w.WriteHeader(http.StatusCreated)
+			json.NewEncoder(w).Encode(newItem)
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	}
+}
+
+func createResourceIDHandler(resource *ResourceConfig) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		if !resource.SupportsID {
+			http.Error(w, "ID operations not supported", http.StatusNotFound)
+			return
+		}
+
+		idStr := strings.TrimPrefix(r.URL.Path, resource.Path+"/")
+		id, err := strconv.Atoi(idStr)
+		if err != nil {
+			http.Error(w, "Invalid ID", http.StatusBadRequest)
+			return
+		}
+
+		var item map[string]interface{}
+		var index int = -1
+		for i, data := range resource.Data {
+			if itemID, ok := data["id"].(float64); ok && int(itemID) == id {
+				item = data
+				index = i
+				break
+			} else if itemID, ok := data["id"].(int); ok && itemID == id {
+				item = data
+				index = i
+				break
+			}
+		}
+
+		if item == nil {
+			http.Error(w, "Item not found", http.StatusNotFound)
+			return
+		}
+
+		switch r.Method {
+		case "GET":
+			json.NewEncoder(w).Encode(item)
+		case "PUT":
+			if !contains(resource.Methods, "PUT") {
+				http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+				return
+			}
+
+			var updatedItem map[string]interface{}
+			if err := json.NewDecoder(r.Body).Decode(&updatedItem); err != nil {
+				http.Error(w, "Invalid JSON", http.StatusBadRequest)
+				return
+			}
+
+			updatedItem["id"] = id
+			if created, exists := item["created"]; exists {
+				updatedItem["created"] = created
+			}
+
+			resource.Data[index] = updatedItem
+			json.NewEncoder(w).Encode(updatedItem)
+		case "DELETE":
+			if !contains(resource.Methods, "DELETE") {
+				http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+				return
+			}
+
+			resource.Data = append(resource.Data[:index], resource.Data[index+1:]...)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	}
+}
+
+func createMultiMethodEndpointHandler(endpoints []*EndpointConfig) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		for _, endpoint := range endpoints {
+			if r.Method == endpoint.Method {
+				createEndpointHandler(endpoint)(w, r)
+				return
+			}
+		}
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func createEndpointHandler(endpoint *EndpointConfig) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != endpoint.Method {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+
+		response := make(map[string]interface{})
+		for key, value := range endpoint.Response {
+			if str, ok := value.(string); ok && str == "{{timestamp}}" {
+				response[key] = time.Now().Format(time.RFC3339)
+			} else {
+				response[key] = value
+			}
+		}
+
+		json.NewEncoder(w).Encode(response)
+	}
+}
+
+func contains(slice []string, item string) bool {
+	for _, s := range slice {
+		if s == item {
+			return true
+		}
+	}
+	return false
+}
+
+// Handler returns an http.Handler serving the configured endpoints and
+// resources
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+
+	authMiddleware := s.basicAuthMiddleware
+	if s.oauth2Enabled() {
+		authMiddleware = s.bearerAuthMiddleware
+		mux.HandleFunc(s.config.Auth.TokenPath, logHeadersMiddleware(s.tokenHandler))
+	}
+
+	// Register endpoint handlers grouped by path
+	endpointsByPath := make(map[string][]*EndpointConfig)
+	for i := range s.config.Endpoints {
+		endpoint := &s.config.Endpoints[i]
+		if endpoint.Enabled {
+			endpointsByPath[endpoint.Path] = append(endpointsByPath[endpoint.Path], endpoint)
+		}
+	}
+
+	for path, endpoints := range endpointsByPath {
+		mux.HandleFunc(path, logHeadersMiddleware(corsHandler(authMiddleware(createMultiMethodEndpointHandler(endpoints)))))
+	}
+
+	// Register resource handlers
+	for i := range s.config.Resources {
+		resource := &s.config.Resources[i]
+		if resource.Enabled {
+			mux.HandleFunc(resource.Path, logHeadersMiddleware(corsHandler(authMiddleware(createResourceHandler(resource)))))
+			if resource.SupportsID {
+				mux.HandleFunc(resource.Path+"/", logHeadersMiddleware(corsHandler(authMiddleware(createResourceIDHandler(resource)))))
+			}
+		}
+	}
+
+	return mux
+}
+
+// PrintInfo writes the server name, authentication settings and available
+// endpoints to w
+func (s *Server) PrintInfo(w io.Writer) {
+	cfg := s.config
+	fmt.Fprintf(w, "%s starting on port %s...\n", cfg.Server.Name, cfg.Server.Port)
+	if s.oauth2Enabled() {
+		fmt.Fprintf(w, "OAuth2 Authentication: ENABLED (client ID: %s, token endpoint: %s)\n", cfg.Auth.Username, cfg.Auth.TokenPath)
+	} else if cfg.Auth.Enabled {
+		fmt.Fprintf(w, "Basic Authentication: ENABLED (username: %s)\n", cfg.Auth.Username)
+	} else {
+		fmt.Fprintln(w, "Basic Authentication: DISABLED")
+	}
+
+	fmt.Fprintln(w, "Available endpoints:")
+	for _, endpoint := range cfg.Endpoints {
+		if endpoint.Enabled {
+			fmt.Fprintf(w, "  %s    %s\n", endpoint.Method, endpoint.Path)
+		}
+	}
+
+	for _, resource := range cfg.Resources {
+		if resource.Enabled {
+			for _, method := range resource.Methods {
+				fmt.Fprintf(w, "  %s    %s\n", method, resource.Path)
+				if resource.SupportsID && (method == "GET" || method == "PUT" || method == "DELETE") {
+					fmt.Fprintf(w, "  %s    %s/{id}\n", method, resource.Path)
+				}
+			}
+		}
+	}
+
+	if s.oauth2Enabled() {
+		fmt.Fprintln(w, "\nTo test with authentication:")
+		fmt.Fprintf(w, "  curl -u %s:%s -d grant_type=client_credentials http://localhost:%s%s\n", cfg.Auth.Username, cfg.Auth.Password, cfg.Server.Port, cfg.Auth.TokenPath)
+		fmt.Fprintf(w, "  curl -H 'Authorization: Bearer <access_token>' http://localhost:%s/users\n", cfg.Server.Port)
+	} else if cfg.Auth.Enabled {
+		fmt.Fprintln(w, "\nTo test with authentication:")
+		fmt.Fprintf(w, "  curl -u %s:%s http://localhost:%s/users\n", cfg.Auth.Username, cfg.Auth.Password, cfg.Server.Port)
+	}
+}
+
+// ListenAndServe serves the mock API on the configured port
+func (s *Server) ListenAndServe() error {
+	return http.ListenAndServe(":"+s.config.Server.Port, s.Handler())
+}
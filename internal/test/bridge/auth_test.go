@@ -187,4 +187,80 @@ func TestRestClient_BasicAuth_WithOtherHeaders(t *testing.T) {
 	resp, err := client.MakeRequest(endpoint, map[string]interface{}{})
 	require.NoError(t, err)
 	assert.Equal(t, http.StatusOK, resp.StatusCode)
-}
\ No newline at end of file
+}
+func TestRestClient_BearerAuth(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer secret-token", r.Header.Get("Authorization"))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := bridge.NewRestClient()
+	endpoint := bridge.APIEndpoint{
+		Name:    "test-bearer",
+		Method:  "GET",
+		Path:    "/test",
+		BaseURL: server.URL,
+		Auth: []config.AuthConfig{
+			{
+				Type:   "bearer",
+				Bearer: &config.BearerAuthConfig{Token: "secret-token"},
+			},
+		},
+	}
+
+	resp, err := client.MakeRequest(endpoint, map[string]interface{}{})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestRestClient_APIKeyAuth(t *testing.T) {
+	tests := []struct {
+		in     string
+		verify func(t *testing.T, r *http.Request)
+	}{
+		{"header", func(t *testing.T, r *http.Request) {
+			assert.Equal(t, "secret", r.Header.Get("X-API-Key"))
+		}},
+		{"query", func(t *testing.T, r *http.Request) {
+			assert.Equal(t, "secret", r.URL.Query().Get("X-API-Key"))
+			assert.Equal(t, "10", r.URL.Query().Get("limit"), "existing query parameters should be kept")
+		}},
+		{"cookie", func(t *testing.T, r *http.Request) {
+			cookie, err := r.Cookie("X-API-Key")
+			require.NoError(t, err)
+			assert.Equal(t, "secret", cookie.Value)
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				tt.verify(t, r)
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			client := bridge.NewRestClient()
+			endpoint := bridge.APIEndpoint{
+				Name:    "test-apikey",
+				Method:  "GET",
+				Path:    "/test",
+				BaseURL: server.URL,
+				Parameters: []bridge.APIParameter{
+					{Name: "limit", Type: "integer", In: "query"},
+				},
+				Auth: []config.AuthConfig{
+					{
+						Type:   "apiKey",
+						APIKey: &config.APIKeyAuthConfig{Name: "X-API-Key", Value: "secret", In: tt.in},
+					},
+				},
+			}
+
+			resp, err := client.MakeRequest(endpoint, map[string]interface{}{"limit": 10})
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+		})
+	}
+}
@@ -0,0 +1,180 @@
+package bridge_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"mcp-bridge/internal/bridge"
+	"mcp-bridge/internal/config"
+	"mcp-bridge/pkg/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newStatusServer answers every request with the status it holds
+func newStatusServer(t *testing.T, status int) (*httptest.Server, *atomic.Int32, *atomic.Int32) {
+	var current, requests atomic.Int32
+	current.Store(int32(status))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(int(current.Load()))
+		w.Write([]byte(`{}`))
+	}))
+	t.Cleanup(server.Close)
+	return server, &current, &requests
+}
+
+func breakerBridge(t *testing.T, tr *channelTransport, baseURL string, breaker *config.CircuitBreakerConfig) *bridge.MCPBridge {
+	t.Helper()
+	mcpBridge, err := bridge.NewFromConfig(tr, breakerConfig(t, baseURL, breaker))
+	require.NoError(t, err)
+	return mcpBridge
+}
+
+// breakerConfig returns a validated config with an "upstream" API using
+// breaker and an "other" API without one
+func breakerConfig(t *testing.T, baseURL string, breaker *config.CircuitBreakerConfig) *config.Config {
+	t.Helper()
+	cfg := &config.Config{APIs: []config.APIConfig{
+		{
+			Name:           "upstream",
+			BaseURL:        baseURL,
+			CircuitBreaker: breaker,
+			Endpoints:      []config.CustomEndpoint{{Name: "get", Method: "GET", Path: "/items"}},
+		},
+		{
+			Name:      "other",
+			BaseURL:   baseURL,
+			Endpoints: []config.CustomEndpoint{{Name: "get", Method: "GET", Path: "/items"}},
+		},
+	}}
+	require.NoError(t, cfg.Validate())
+	return cfg
+}
+
+func readStatus(t *testing.T, mcpBridge *bridge.MCPBridge, tr *channelTransport) map[string]bridge.APIStatus {
+	t.Helper()
+	resp := call(t, mcpBridge, tr, "resources/read", map[string]interface{}{"uri": "rest-api://status"})
+	require.Nil(t, resp.Error)
+	var status struct {
+		APIs map[string]bridge.APIStatus `json:"apis"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(resp.Result.(*types.ReadResourceResult).Contents[0].Text), &status))
+	return status.APIs
+}
+
+func TestMCPBridge_CircuitBreakerOpens(t *testing.T) {
+	server, _, requests := newStatusServer(t, http.StatusServiceUnavailable)
+	tr := newChannelTransport()
+	mcpBridge := breakerBridge(t, tr, server.URL, &config.CircuitBreakerConfig{FailureThreshold: 2})
+
+	for i := 0; i < 2; i++ {
+		result := callPaged(t, mcpBridge, "upstream__get", nil)
+		assert.Contains(t, result.Content[0].Text, "API Error: HTTP 503")
+	}
+
+	result := callPaged(t, mcpBridge, "upstream__get", nil)
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].Text,
+		"API upstream is unavailable: the circuit breaker opened after 2 consecutive failures (last: HTTP 503); calls are rejected for another 30s")
+	assert.Equal(t, int32(2), requests.Load(), "calls are rejected without being sent")
+
+	// Other APIs are not affected
+	result = callPaged(t, mcpBridge, "other__get", nil)
+	assert.Contains(t, result.Content[0].Text, "API Error: HTTP 503")
+	assert.Equal(t, int32(3), requests.Load())
+
+	status := readStatus(t, mcpBridge, tr)
+	require.NotNil(t, status["upstream"].CircuitBreaker)
+	assert.Equal(t, "open", status["upstream"].CircuitBreaker.State)
+	assert.Equal(t, 2, status["upstream"].CircuitBreaker.ConsecutiveFailures)
+	assert.Equal(t, "HTTP 503", status["upstream"].CircuitBreaker.LastFailure)
+	assert.NotNil(t, status["upstream"].CircuitBreaker.OpenUntil)
+	assert.Equal(t, 1, status["upstream"].Endpoints)
+	assert.Nil(t, status["other"].CircuitBreaker)
+}
+
+func TestMCPBridge_CircuitBreakerHalfOpen(t *testing.T) {
+	server, current, requests := newStatusServer(t, http.StatusBadGateway)
+	tr := newChannelTransport()
+	mcpBridge := breakerBridge(t, tr, server.URL, &config.CircuitBreakerConfig{FailureThreshold: 1, OpenSeconds: 1})
+
+	callPaged(t, mcpBridge, "upstream__get", nil)
+	result := callPaged(t, mcpBridge, "upstream__get", nil)
+	assert.Contains(t, result.Content[0].Text, "circuit breaker opened")
+
+	// A failed probe opens the breaker again
+	time.Sleep(1100 * time.Millisecond)
+	result = callPaged(t, mcpBridge, "upstream__get", nil)
+	assert.Contains(t, result.Content[0].Text, "API Error: HTTP 502")
+	result = callPaged(t, mcpBridge, "upstream__get", nil)
+	assert.Contains(t, result.Content[0].Text, "circuit breaker opened")
+	assert.Equal(t, int32(2), requests.Load())
+
+	// A successful probe closes it
+	current.Store(http.StatusOK)
+	time.Sleep(1100 * time.Millisecond)
+	for i := 0; i < 3; i++ {
+		result = callPaged(t, mcpBridge, "upstream__get", nil)
+		assert.False(t, result.IsError)
+	}
+	assert.Equal(t, int32(5), requests.Load())
+
+	status := readStatus(t, mcpBridge, tr)
+	assert.Equal(t, bridge.BreakerStatus{State: "closed"}, *status["upstream"].CircuitBreaker)
+}
+
+func TestMCPBridge_CircuitBreakerIgnoresClientErrors(t *testing.T) {
+	server, _, requests := newStatusServer(t, http.StatusNotFound)
+	mcpBridge := breakerBridge(t, newChannelTransport(), server.URL, &config.CircuitBreakerConfig{FailureThreshold: 1})
+
+	for i := 0; i < 3; i++ {
+		result := callPaged(t, mcpBridge, "upstream__get", nil)
+		assert.Contains(t, result.Content[0].Text, "API Error: HTTP 404")
+	}
+	assert.Equal(t, int32(3), requests.Load())
+}
+
+func TestMCPBridge_CircuitBreakerConnectionErrors(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	baseURL := server.URL
+	server.Close()
+	mcpBridge := breakerBridge(t, newChannelTransport(), baseURL, &config.CircuitBreakerConfig{FailureThreshold: 1})
+
+	result, err := mcpBridge.CallTool(context.Background(), "upstream__get", nil)
+	require.NoError(t, err)
+	assert.Contains(t, result.Content[0].Text, "connection refused")
+
+	result, err = mcpBridge.CallTool(context.Background(), "upstream__get", nil)
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].Text, "the circuit breaker opened after 1 consecutive failures (last: error making request:")
+}
+
+func TestMCPBridge_CircuitBreakerSurvivesReload(t *testing.T) {
+	server, _, requests := newStatusServer(t, http.StatusServiceUnavailable)
+	tr := newChannelTransport()
+	mcpBridge := breakerBridge(t, tr, server.URL, &config.CircuitBreakerConfig{FailureThreshold: 1})
+
+	callPaged(t, mcpBridge, "upstream__get", nil)
+	assert.True(t, callPaged(t, mcpBridge, "upstream__get", nil).IsError)
+	assert.Equal(t, int32(1), requests.Load())
+
+	// Reloading an unchanged breaker keeps it open
+	require.NoError(t, mcpBridge.ApplyConfig(breakerConfig(t, server.URL, &config.CircuitBreakerConfig{FailureThreshold: 1})))
+	result := callPaged(t, mcpBridge, "upstream__get", nil)
+	assert.Contains(t, result.Content[0].Text, "the circuit breaker opened")
+	assert.Equal(t, int32(1), requests.Load())
+
+	// Changing its settings starts over with a closed breaker
+	require.NoError(t, mcpBridge.ApplyConfig(breakerConfig(t, server.URL, &config.CircuitBreakerConfig{FailureThreshold: 2})))
+	result = callPaged(t, mcpBridge, "upstream__get", nil)
+	assert.Contains(t, result.Content[0].Text, "API Error: HTTP 503")
+	assert.Equal(t, int32(2), requests.Load())
+}
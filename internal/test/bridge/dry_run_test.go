@@ -0,0 +1,169 @@
+package bridge_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"mcp-bridge/internal/bridge"
+	"mcp-bridge/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func dryRunConfig(baseURL string, serverDryRun, apiDryRun *config.DryRunConfig) *config.Config {
+	cfg := &config.Config{
+		APIs: []config.APIConfig{{
+			Name:    "users",
+			BaseURL: baseURL,
+			Auth:    []config.AuthConfig{{Type: "bearer", Bearer: &config.BearerAuthConfig{Token: "s3cr3t"}}},
+			DryRun:  apiDryRun,
+			Endpoints: []config.CustomEndpoint{
+				{Name: "list", Method: "GET", Path: "/users"},
+				{Name: "update", Method: "PUT", Path: "/users/{id}", Parameters: []config.CustomParameter{
+					{Name: "id", In: "path", Required: true},
+					{Name: "name", In: "body"},
+				}},
+			},
+		}},
+		Server: config.ServerConfig{DryRun: serverDryRun},
+	}
+	if err := cfg.Validate(); err != nil {
+		panic(err)
+	}
+	return cfg
+}
+
+func TestMCPBridge_DryRunUnsafeOnly(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	cfg := dryRunConfig(server.URL, &config.DryRunConfig{Enabled: true, UnsafeOnly: true}, nil)
+	mcpBridge, err := bridge.NewFromConfig(nil, cfg)
+	require.NoError(t, err)
+
+	result, err := mcpBridge.CallTool(context.Background(), "users__list", nil)
+	require.NoError(t, err)
+	assert.False(t, result.IsError)
+	assert.Contains(t, result.Content[0].Text, "Status: 200")
+	assert.Equal(t, int32(1), requests.Load(), "GET requests are sent")
+
+	result, err = mcpBridge.CallTool(context.Background(), "users__update", map[string]interface{}{"id": "7", "name": "Jane"})
+	require.NoError(t, err)
+	assert.False(t, result.IsError)
+	assert.Equal(t, int32(1), requests.Load(), "PUT requests are not sent")
+
+	text := result.Content[0].Text
+	assert.Contains(t, text, "Dry run: the request was not sent.")
+	assert.Contains(t, text, "PUT "+server.URL+"/users/7\n")
+	assert.Contains(t, text, "Authorization: Bearer ****\n")
+	assert.Contains(t, text, `{"name":"Jane"}`)
+	assert.NotContains(t, text, "s3cr3t")
+
+	var descriptions []string
+	for _, tool := range mcpBridge.Tools() {
+		descriptions = append(descriptions, tool.Description)
+	}
+	assert.NotContains(t, descriptions[0], "dry run")
+	assert.Contains(t, descriptions[1], "[dry run")
+}
+
+func TestMCPBridge_DryRunAPIOverridesServer(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	cfg := dryRunConfig(server.URL, &config.DryRunConfig{Enabled: true}, &config.DryRunConfig{Enabled: false})
+	mcpBridge, err := bridge.NewFromConfig(nil, cfg)
+	require.NoError(t, err)
+
+	result, err := mcpBridge.CallTool(context.Background(), "users__update", map[string]interface{}{"id": "7"})
+	require.NoError(t, err)
+	assert.NotContains(t, result.Content[0].Text, "Dry run")
+	assert.Equal(t, int32(1), requests.Load())
+}
+
+func TestDryRunConfig_Applies(t *testing.T) {
+	var unset *config.DryRunConfig
+	assert.False(t, unset.Applies("DELETE"))
+	assert.True(t, (&config.DryRunConfig{Enabled: true}).Applies("GET"))
+
+	unsafeOnly := &config.DryRunConfig{Enabled: true, UnsafeOnly: true}
+	for _, method := range []string{"GET", "head", "OPTIONS"} {
+		assert.False(t, unsafeOnly.Applies(method), method)
+	}
+	for _, method := range []string{"POST", "PUT", "PATCH", "delete"} {
+		assert.True(t, unsafeOnly.Applies(method), method)
+	}
+}
+
+func TestEndpointsFromConfig_AllowedMethods(t *testing.T) {
+	cfg := dryRunConfig("http://localhost:8080", nil, nil)
+	cfg.APIs[0].Endpoints = append(cfg.APIs[0].Endpoints, config.CustomEndpoint{Name: "remove", Method: "delete", Path: "/users"})
+	cfg.Server.AllowedMethods = config.SafeMethods
+
+	var names []string
+	for _, endpoint := range bridge.EndpointsFromConfig(cfg) {
+		names = append(names, endpoint.Name)
+	}
+	assert.Equal(t, []string{"users__list"}, names)
+
+	cfg.APIs[0].AllowedMethods = []string{"GET", "DELETE"}
+	names = nil
+	for _, endpoint := range bridge.EndpointsFromConfig(cfg) {
+		names = append(names, endpoint.Name)
+	}
+	assert.Equal(t, []string{"users__list", "users__remove"}, names, "the API's list overrides the server's")
+
+	mcpBridge, err := bridge.NewFromConfig(nil, cfg)
+	require.NoError(t, err)
+	result, err := mcpBridge.CallTool(context.Background(), "users__update", map[string]interface{}{"id": "7"})
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].Text, "Unknown tool: users__update")
+}
+
+func TestMCPBridge_QueryAPIKeyMasked(t *testing.T) {
+	const key = "c2VjcmV0+a2V5/MTIz=="
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	closedURL := server.URL
+	server.Close()
+
+	cfg := &config.Config{
+		APIs: []config.APIConfig{{
+			Name:    "users",
+			BaseURL: closedURL,
+			Auth:    []config.AuthConfig{{Type: "apiKey", APIKey: &config.APIKeyAuthConfig{Name: "key", Value: key, In: "query"}}},
+			Endpoints: []config.CustomEndpoint{
+				{Name: "list", Method: "GET", Path: "/users"},
+				{Name: "delete", Method: "DELETE", Path: "/users"},
+			},
+		}},
+		Server: config.ServerConfig{DryRun: &config.DryRunConfig{Enabled: true, UnsafeOnly: true}},
+	}
+	require.NoError(t, cfg.Validate())
+	mcpBridge, err := bridge.NewFromConfig(nil, cfg)
+	require.NoError(t, err)
+
+	// The request to the closed server fails with a *url.Error quoting the URL
+	result, err := mcpBridge.CallTool(context.Background(), "users__list", nil)
+	require.NoError(t, err)
+	require.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].Text, "/users?key=****")
+	assert.NotContains(t, result.Content[0].Text, "c2VjcmV0")
+
+	result, err = mcpBridge.CallTool(context.Background(), "users__delete", nil)
+	require.NoError(t, err)
+	assert.Contains(t, result.Content[0].Text, "DELETE "+closedURL+"/users?key=****\n")
+	assert.NotContains(t, result.Content[0].Text, "c2VjcmV0")
+}
@@ -1,14 +1,72 @@
 package bridge_test
 
 import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
 	"mcp-bridge/internal/bridge"
+	"mcp-bridge/internal/config"
 	"mcp-bridge/internal/transport"
+	"mcp-bridge/pkg/types"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
+// channelTransport feeds queued messages to the bridge and collects its output
+type channelTransport struct {
+	in  chan *types.JSONRPCMessage
+	out chan *types.JSONRPCMessage
+}
+
+func newChannelTransport() *channelTransport {
+	return &channelTransport{
+		in:  make(chan *types.JSONRPCMessage, 100),
+		out: make(chan *types.JSONRPCMessage, 100),
+	}
+}
+
+func (t *channelTransport) Start() error { return nil }
+
+func (t *channelTransport) ReadMessage() (*types.JSONRPCMessage, error) {
+	msg, ok := <-t.in
+	if !ok {
+		return nil, io.EOF
+	}
+	return msg, nil
+}
+
+func (t *channelTransport) WriteMessage(msg *types.JSONRPCMessage) error {
+	t.out <- msg
+	return nil
+}
+
+func (t *channelTransport) Close() error { return nil }
+
+func (t *channelTransport) expect(tb testing.TB) *types.JSONRPCMessage {
+	tb.Helper()
+	select {
+	case msg := <-t.out:
+		return msg
+	case <-time.After(2 * time.Second):
+		tb.Fatal("timed out waiting for response")
+		return nil
+	}
+}
+
+// call sends a single request to a bridge serving tr and returns its response
+func call(t *testing.T, mcpBridge *bridge.MCPBridge, tr *channelTransport, method string, params interface{}) *types.JSONRPCMessage {
+	t.Helper()
+	tr.in <- &types.JSONRPCMessage{JSONRpc: "2.0", ID: 1, Method: method, Params: params}
+	close(tr.in)
+	require.NoError(t, mcpBridge.Start())
+	return tr.expect(t)
+}
+
 func TestNewMCPBridge(t *testing.T) {
 	mockTransport := transport.NewStdioTransport()
 	mcpBridge := bridge.NewMCPBridge(mockTransport)
@@ -58,3 +116,117 @@ func TestMCPBridge_FullWorkflow(t *testing.T) {
 
 	mcpBridge.AddCustomEndpoint(endpoint)
 }
+
+func TestMCPBridge_DocsMaskSecrets(t *testing.T) {
+	tr := newChannelTransport()
+	mcpBridge := bridge.NewMCPBridge(tr)
+	mcpBridge.SetMasker(config.NewMasker([]string{"super-secret-key"}))
+
+	mcpBridge.AddCustomEndpoint(bridge.APIEndpoint{
+		Name:    "test-docs",
+		Method:  "GET",
+		Path:    "/test",
+		APIName: "test-api",
+		Headers: map[string]string{"X-API-Key": "super-secret-key"},
+		Auth: []config.AuthConfig{
+			{Type: "bearer", Bearer: &config.BearerAuthConfig{Token: "bearer-token"}},
+		},
+	})
+
+	resp := call(t, mcpBridge, tr, "resources/read", map[string]interface{}{"uri": "rest-api://docs"})
+	require.Nil(t, resp.Error)
+	text := resp.Result.(*types.ReadResourceResult).Contents[0].Text
+
+	assert.Contains(t, text, "test-docs")
+	assert.Contains(t, text, config.MaskedValue)
+	assert.NotContains(t, text, "super-secret-key")
+	assert.NotContains(t, text, "bearer-token")
+}
+
+func TestMCPBridge_ToolAnnotations(t *testing.T) {
+	mcpBridge := bridge.NewMCPBridge(newChannelTransport())
+	readOnly := true
+	for _, endpoint := range []bridge.APIEndpoint{
+		{Name: "list", Method: "GET", Path: "/items"},
+		{Name: "create", Method: "POST", Path: "/items"},
+		{Name: "replace", Method: "put", Path: "/items/{id}"},
+		{Name: "update", Method: "PATCH", Path: "/items/{id}"},
+		{Name: "delete", Method: "DELETE", Path: "/items/{id}"},
+		{Name: "preview_delete", Method: "DELETE", Path: "/items/{id}", DryRun: true},
+		{Name: "search", Method: "POST", Path: "/search", Annotations: &config.ToolAnnotations{
+			Title:        "Search items",
+			ReadOnlyHint: &readOnly,
+		}},
+	} {
+		mcpBridge.AddCustomEndpoint(endpoint)
+	}
+
+	hints := make(map[string][]interface{})
+	for _, tool := range mcpBridge.Tools() {
+		a := tool.Annotations
+		require.NotNil(t, a, tool.Name)
+		assert.True(t, *a.OpenWorldHint, tool.Name)
+		hints[tool.Name] = []interface{}{*a.ReadOnlyHint, a.DestructiveHint, a.IdempotentHint}
+	}
+
+	yes, no := true, false
+	assert.Equal(t, []interface{}{true, (*bool)(nil), (*bool)(nil)}, hints["list"])
+	assert.Equal(t, []interface{}{false, (*bool)(nil), &no}, hints["create"])
+	assert.Equal(t, []interface{}{false, &yes, &yes}, hints["replace"])
+	assert.Equal(t, []interface{}{false, &yes, &no}, hints["update"])
+	assert.Equal(t, []interface{}{false, &yes, &yes}, hints["delete"])
+	assert.Equal(t, []interface{}{true, (*bool)(nil), (*bool)(nil)}, hints["preview_delete"])
+	assert.Equal(t, []interface{}{true, (*bool)(nil), &no}, hints["search"])
+	assert.Equal(t, "Search items", mcpBridge.Tools()[6].Annotations.Title)
+}
+
+func TestMCPBridge_StructuredContent(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/users/1":
+			w.Write([]byte(`{"id": 1, "name": "Jane"}`))
+		case "/users":
+			w.Write([]byte(`[{"id": 1}]`))
+		default:
+			w.Write([]byte(`plain text`))
+		}
+	}))
+	defer server.Close()
+
+	userSchema := map[string]interface{}{"type": "object", "properties": map[string]interface{}{"id": map[string]interface{}{"type": "integer"}}}
+	listSchema := map[string]interface{}{"type": "array", "items": userSchema}
+	cfg := &config.Config{APIs: []config.APIConfig{{
+		Name:    "users",
+		BaseURL: server.URL,
+		Endpoints: []config.CustomEndpoint{
+			{Name: "get", Method: "GET", Path: "/users/1", OutputSchema: userSchema},
+			{Name: "list", Method: "GET", Path: "/users", OutputSchema: listSchema},
+			{Name: "list_untyped", Method: "GET", Path: "/users"},
+			{Name: "text", Method: "GET", Path: "/text"},
+		},
+	}}}
+	require.NoError(t, cfg.Validate())
+	mcpBridge, err := bridge.NewFromConfig(nil, cfg)
+	require.NoError(t, err)
+
+	tools := mcpBridge.Tools()
+	assert.Equal(t, userSchema, tools[0].OutputSchema)
+	assert.Equal(t, map[string]interface{}{
+		"type":       "object",
+		"properties": map[string]interface{}{"result": listSchema},
+		"required":   []string{"result"},
+	}, tools[1].OutputSchema, "non-object schemas are wrapped")
+	assert.Nil(t, tools[2].OutputSchema)
+
+	structured := func(name string) map[string]interface{} {
+		result, err := mcpBridge.CallTool(context.Background(), name, nil)
+		require.NoError(t, err)
+		require.False(t, result.IsError)
+		assert.Contains(t, result.Content[0].Text, "Status: 200")
+		return result.StructuredContent
+	}
+	assert.Equal(t, map[string]interface{}{"id": float64(1), "name": "Jane"}, structured("users__get"))
+	assert.Equal(t, map[string]interface{}{"result": []interface{}{map[string]interface{}{"id": float64(1)}}}, structured("users__list"))
+	assert.Equal(t, map[string]interface{}{"result": []interface{}{map[string]interface{}{"id": float64(1)}}}, structured("users__list_untyped"))
+	assert.Nil(t, structured("users__text"))
+}
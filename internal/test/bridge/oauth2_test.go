@@ -0,0 +1,258 @@
+package bridge_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"mcp-bridge/internal/bridge"
+	"mcp-bridge/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// tokenServer is a stand-in OAuth2 token endpoint that issues numbered tokens
+type tokenServer struct {
+	*httptest.Server
+	issued    int32
+	expiresIn int
+
+	mu       sync.Mutex
+	lastForm map[string]string
+	revoked  map[string]bool
+}
+
+func newTokenServer(t *testing.T, expiresIn int) *tokenServer {
+	ts := &tokenServer{expiresIn: expiresIn, revoked: make(map[string]bool)}
+	ts.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+
+		clientID, clientSecret, ok := r.BasicAuth()
+		if !ok {
+			clientID, clientSecret = r.PostForm.Get("client_id"), r.PostForm.Get("client_secret")
+		}
+		if clientID != "client" || clientSecret != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid_client"})
+			return
+		}
+
+		ts.mu.Lock()
+		ts.lastForm = make(map[string]string)
+		for key := range r.PostForm {
+			ts.lastForm[key] = r.PostForm.Get(key)
+		}
+		ts.mu.Unlock()
+
+		n := atomic.AddInt32(&ts.issued, 1)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token": fmt.Sprintf("token-%d", n),
+			"token_type":   "Bearer",
+			"expires_in":   ts.expiresIn,
+		})
+	}))
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func (ts *tokenServer) revoke(token string) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	ts.revoked[token] = true
+}
+
+func (ts *tokenServer) isRevoked(token string) bool {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	return ts.revoked[token]
+}
+
+// newProtectedAPI returns an API server that accepts any unrevoked token and
+// echoes the token it received
+func newProtectedAPI(t *testing.T, ts *tokenServer) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := r.Header.Get("Authorization")
+		if len(token) < 7 || ts.isRevoked(token[7:]) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{"token": token[7:]})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func oauth2Endpoint(baseURL string, cfg *config.OAuth2AuthConfig) bridge.APIEndpoint {
+	return bridge.APIEndpoint{
+		Name:    "test-oauth2",
+		Method:  "GET",
+		Path:    "/test",
+		BaseURL: baseURL,
+		APIName: "oauth2-api",
+		Auth:    []config.AuthConfig{{Type: "oauth2", OAuth2: cfg}},
+	}
+}
+
+func receivedToken(t *testing.T, resp *bridge.APIResponse) string {
+	t.Helper()
+	require.Equal(t, http.StatusOK, resp.StatusCode, resp.Body)
+	return resp.Data.(map[string]interface{})["token"].(string)
+}
+
+func TestRestClient_OAuth2_CachesToken(t *testing.T) {
+	ts := newTokenServer(t, 3600)
+	api := newProtectedAPI(t, ts)
+
+	client := bridge.NewRestClient()
+	endpoint := oauth2Endpoint(api.URL, &config.OAuth2AuthConfig{
+		TokenURL:     ts.URL,
+		ClientID:     "client",
+		ClientSecret: "secret",
+		Scopes:       []string{"read", "write"},
+		Audience:     "https://api.example.com",
+		Params:       map[string]string{"resource": "users"},
+	})
+
+	for i := 0; i < 3; i++ {
+		resp, err := client.MakeRequest(endpoint, map[string]interface{}{})
+		require.NoError(t, err)
+		assert.Equal(t, "token-1", receivedToken(t, resp))
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(&ts.issued))
+
+	assert.Equal(t, map[string]string{
+		"grant_type": "client_credentials",
+		"scope":      "read write",
+		"audience":   "https://api.example.com",
+		"resource":   "users",
+	}, ts.lastForm)
+}
+
+func TestRestClient_OAuth2_ResetKeepsUnchangedToken(t *testing.T) {
+	ts := newTokenServer(t, 3600)
+	api := newProtectedAPI(t, ts)
+
+	client := bridge.NewRestClient()
+	oauth2Config := func(scopes ...string) *config.OAuth2AuthConfig {
+		return &config.OAuth2AuthConfig{TokenURL: ts.URL, ClientID: "client", ClientSecret: "secret", Scopes: scopes}
+	}
+	endpoint := oauth2Endpoint(api.URL, oauth2Config("read"))
+
+	resp, err := client.MakeRequest(endpoint, map[string]interface{}{})
+	require.NoError(t, err)
+	assert.Equal(t, "token-1", receivedToken(t, resp))
+
+	// An identical configuration keeps the cached token
+	endpoint = oauth2Endpoint(api.URL, oauth2Config("read"))
+	client.Reset(nil, []bridge.APIEndpoint{endpoint})
+	resp, err = client.MakeRequest(endpoint, map[string]interface{}{})
+	require.NoError(t, err)
+	assert.Equal(t, "token-1", receivedToken(t, resp))
+
+	// Changed OAuth2 settings fetch a new one
+	endpoint = oauth2Endpoint(api.URL, oauth2Config("read", "write"))
+	client.Reset(nil, []bridge.APIEndpoint{endpoint})
+	resp, err = client.MakeRequest(endpoint, map[string]interface{}{})
+	require.NoError(t, err)
+	assert.Equal(t, "token-2", receivedToken(t, resp))
+
+	// So does a changed client
+	endpoint.Timeout = 10
+	client.Reset(nil, []bridge.APIEndpoint{endpoint})
+	resp, err = client.MakeRequest(endpoint, map[string]interface{}{})
+	require.NoError(t, err)
+	assert.Equal(t, "token-3", receivedToken(t, resp))
+}
+
+func TestRestClient_OAuth2_ClientCredentialsInBody(t *testing.T) {
+	ts := newTokenServer(t, 3600)
+	api := newProtectedAPI(t, ts)
+
+	client := bridge.NewRestClient()
+	endpoint := oauth2Endpoint(api.URL, &config.OAuth2AuthConfig{
+		TokenURL:     ts.URL,
+		ClientID:     "client",
+		ClientSecret: "secret",
+		ClientAuth:   "body",
+	})
+
+	resp, err := client.MakeRequest(endpoint, map[string]interface{}{})
+	require.NoError(t, err)
+	assert.Equal(t, "token-1", receivedToken(t, resp))
+	assert.Equal(t, "client", ts.lastForm["client_id"])
+}
+
+func TestRestClient_OAuth2_RefreshesBeforeExpiry(t *testing.T) {
+	// A two second token is refreshed after half its lifetime
+	ts := newTokenServer(t, 2)
+	api := newProtectedAPI(t, ts)
+
+	client := bridge.NewRestClient()
+	endpoint := oauth2Endpoint(api.URL, &config.OAuth2AuthConfig{
+		TokenURL:     ts.URL,
+		ClientID:     "client",
+		ClientSecret: "secret",
+	})
+
+	resp, err := client.MakeRequest(endpoint, map[string]interface{}{})
+	require.NoError(t, err)
+	assert.Equal(t, "token-1", receivedToken(t, resp))
+
+	time.Sleep(1100 * time.Millisecond)
+
+	resp, err = client.MakeRequest(endpoint, map[string]interface{}{})
+	require.NoError(t, err)
+	assert.Equal(t, "token-2", receivedToken(t, resp))
+}
+
+func TestRestClient_OAuth2_RetriesOnceOnUnauthorized(t *testing.T) {
+	ts := newTokenServer(t, 3600)
+	api := newProtectedAPI(t, ts)
+
+	client := bridge.NewRestClient()
+	endpoint := oauth2Endpoint(api.URL, &config.OAuth2AuthConfig{
+		TokenURL:     ts.URL,
+		ClientID:     "client",
+		ClientSecret: "secret",
+	})
+
+	resp, err := client.MakeRequest(endpoint, map[string]interface{}{})
+	require.NoError(t, err)
+	assert.Equal(t, "token-1", receivedToken(t, resp))
+
+	ts.revoke("token-1")
+
+	resp, err = client.MakeRequest(endpoint, map[string]interface{}{})
+	require.NoError(t, err)
+	assert.Equal(t, "token-2", receivedToken(t, resp))
+
+	// A 401 with a fresh token is returned rather than retried again
+	ts.revoke("token-2")
+	ts.revoke("token-3")
+
+	resp, err = client.MakeRequest(endpoint, map[string]interface{}{})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&ts.issued))
+}
+
+func TestRestClient_OAuth2_TokenError(t *testing.T) {
+	ts := newTokenServer(t, 3600)
+
+	client := bridge.NewRestClient()
+	endpoint := oauth2Endpoint("http://localhost:8080", &config.OAuth2AuthConfig{
+		TokenURL:     ts.URL,
+		ClientID:     "client",
+		ClientSecret: "wrong",
+	})
+
+	_, err := client.MakeRequest(endpoint, map[string]interface{}{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "token endpoint returned HTTP 401: invalid_client")
+}
@@ -0,0 +1,255 @@
+package bridge_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"mcp-bridge/internal/bridge"
+	"mcp-bridge/internal/config"
+	"mcp-bridge/pkg/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newPagedServer serves 7 items, 3 per page, using the pagination style of
+// each path
+func newPagedServer(t *testing.T) (*httptest.Server, *[]string) {
+	var requests []string
+	page := func(start int) string {
+		items := ""
+		for i := start; i < start+3 && i < 7; i++ {
+			if items != "" {
+				items += ","
+			}
+			items += fmt.Sprintf(`{"id": %d}`, i)
+		}
+		return "[" + items + "]"
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.RequestURI())
+		query := r.URL.Query()
+		switch r.URL.Path {
+		case "/link":
+			start, _ := strconv.Atoi(query.Get("from"))
+			if start+3 < 7 {
+				w.Header().Set("Link", fmt.Sprintf(`</link?from=%d>; rel="next", </link?from=6>; rel="last"`, start+3))
+			}
+			fmt.Fprint(w, page(start))
+		case "/cursor":
+			start, _ := strconv.Atoi(query.Get("after"))
+			next := "null"
+			if start+3 < 7 {
+				next = strconv.Quote(strconv.Itoa(start + 3))
+			}
+			fmt.Fprintf(w, `{"data": %s, "meta": {"next": %s}}`, page(start), next)
+		case "/page":
+			n, _ := strconv.Atoi(query.Get("p"))
+			fmt.Fprint(w, page((n-1)*3))
+		case "/offset":
+			offset, _ := strconv.Atoi(query.Get("offset"))
+			fmt.Fprint(w, page(offset))
+		case "/evil":
+			w.Header().Set("Link", `<http://attacker.example/steal>; rel="next"`)
+			fmt.Fprint(w, page(0))
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func pagedBridge(t *testing.T, baseURL string, endpoints ...config.CustomEndpoint) *bridge.MCPBridge {
+	t.Helper()
+	cfg := &config.Config{APIs: []config.APIConfig{{Name: "paged", BaseURL: baseURL, Endpoints: endpoints}}}
+	require.NoError(t, cfg.Validate())
+	mcpBridge, err := bridge.NewFromConfig(nil, cfg)
+	require.NoError(t, err)
+	return mcpBridge
+}
+
+func callPaged(t *testing.T, mcpBridge *bridge.MCPBridge, name string, args map[string]interface{}) *types.CallToolResult {
+	t.Helper()
+	result, err := mcpBridge.CallTool(context.Background(), name, args)
+	require.NoError(t, err)
+	return result
+}
+
+func TestMCPBridge_PaginationAggregate(t *testing.T) {
+	server, requests := newPagedServer(t)
+	mcpBridge := pagedBridge(t, server.URL,
+		config.CustomEndpoint{Name: "link", Method: "GET", Path: "/link", Pagination: &config.PaginationConfig{Type: "link"}},
+		config.CustomEndpoint{Name: "cursor", Method: "GET", Path: "/cursor", Pagination: &config.PaginationConfig{
+			Type: "cursor", Items: "$.data", CursorPath: "$.meta.next", CursorParam: "after",
+		}},
+		config.CustomEndpoint{Name: "page", Method: "GET", Path: "/page", Pagination: &config.PaginationConfig{Type: "page", PageParam: "p"}},
+		config.CustomEndpoint{Name: "offset", Method: "GET", Path: "/offset", Pagination: &config.PaginationConfig{Type: "offset"}},
+	)
+
+	for _, name := range []string{"link", "cursor", "page", "offset"} {
+		result := callPaged(t, mcpBridge, "paged__"+name, nil)
+		require.False(t, result.IsError, result.Content[0].Text)
+		assert.Contains(t, result.Content[0].Text, "Note: fetched 7 items from ", name)
+		items := result.StructuredContent["result"].([]interface{})
+		require.Len(t, items, 7, name)
+		assert.Equal(t, float64(6), items[6].(map[string]interface{})["id"], name)
+	}
+
+	assert.Equal(t, []string{
+		"/link", "/link?from=3", "/link?from=6",
+		"/cursor", "/cursor?after=3", "/cursor?after=6",
+		"/page", "/page?p=2", "/page?p=3", "/page?p=4",
+		"/offset", "/offset?offset=3", "/offset?offset=6", "/offset?offset=7",
+	}, *requests)
+}
+
+func TestMCPBridge_PaginationLimits(t *testing.T) {
+	server, requests := newPagedServer(t)
+	mcpBridge := pagedBridge(t, server.URL,
+		config.CustomEndpoint{Name: "pages", Method: "GET", Path: "/link", Pagination: &config.PaginationConfig{Type: "link", MaxPages: 2}},
+		config.CustomEndpoint{Name: "items", Method: "GET", Path: "/link", Pagination: &config.PaginationConfig{Type: "link", MaxItems: 4}},
+		config.CustomEndpoint{Name: "evil", Method: "GET", Path: "/evil", Pagination: &config.PaginationConfig{Type: "link"}},
+	)
+
+	result := callPaged(t, mcpBridge, "paged__pages", nil)
+	assert.Contains(t, result.Content[0].Text, "Note: fetched 6 items from 2 pages; more items are available\n")
+
+	result = callPaged(t, mcpBridge, "paged__items", nil)
+	assert.Contains(t, result.Content[0].Text, "Note: fetched 4 items from 2 pages; more items are available\n")
+	assert.Len(t, result.StructuredContent["result"], 4)
+
+	*requests = nil
+	result = callPaged(t, mcpBridge, "paged__evil", nil)
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].Text, "next page URL http://attacker.example/steal is not on")
+	assert.Equal(t, []string{"/evil"}, *requests)
+}
+
+func TestMCPBridge_PaginationAggregateCursorParameter(t *testing.T) {
+	server, requests := newPagedServer(t)
+	mcpBridge := pagedBridge(t, server.URL, config.CustomEndpoint{
+		Name:       "list",
+		Method:     "GET",
+		Path:       "/link",
+		Parameters: []config.CustomParameter{{Name: "cursor", In: "query"}},
+		Pagination: &config.PaginationConfig{Type: "link"},
+	})
+
+	result := callPaged(t, mcpBridge, "paged__list", map[string]interface{}{"cursor": "abc"})
+	require.False(t, result.IsError, result.Content[0].Text)
+	assert.Equal(t, "/link?cursor=abc", (*requests)[0], "the argument is sent as the endpoint's parameter")
+}
+
+func TestMCPBridge_PaginationCursorMode(t *testing.T) {
+	server, requests := newPagedServer(t)
+	mcpBridge := pagedBridge(t, server.URL,
+		config.CustomEndpoint{Name: "list", Method: "GET", Path: "/cursor", Pagination: &config.PaginationConfig{
+			Type: "cursor", Mode: "cursor", Items: "data", CursorPath: "meta.next", CursorParam: "after",
+		}},
+		config.CustomEndpoint{Name: "link", Method: "GET", Path: "/link", Pagination: &config.PaginationConfig{Type: "link", Mode: "cursor"}},
+	)
+
+	properties := mcpBridge.Tools()[0].InputSchema.(map[string]interface{})["properties"].(map[string]interface{})
+	assert.Contains(t, properties, "cursor")
+
+	result := callPaged(t, mcpBridge, "paged__list", nil)
+	assert.Contains(t, result.Content[0].Text, `Note: more results are available: call again with cursor "3"`)
+	assert.Len(t, result.StructuredContent["data"], 3)
+
+	result = callPaged(t, mcpBridge, "paged__list", map[string]interface{}{"cursor": "6"})
+	assert.NotContains(t, result.Content[0].Text, "more results")
+	assert.Len(t, result.StructuredContent["data"], 1)
+	assert.Equal(t, []string{"/cursor", "/cursor?after=6"}, *requests)
+
+	result = callPaged(t, mcpBridge, "paged__link", nil)
+	assert.Contains(t, result.Content[0].Text, fmt.Sprintf(`call again with cursor "%s/link?from=3"`, server.URL))
+
+	result = callPaged(t, mcpBridge, "paged__link", map[string]interface{}{"cursor": "http://attacker.example/link"})
+	assert.True(t, result.IsError)
+}
+
+func TestMCPBridge_PaginationOutputSchema(t *testing.T) {
+	server, _ := newPagedServer(t)
+	item := map[string]interface{}{
+		"type":       "object",
+		"properties": map[string]interface{}{"id": map[string]interface{}{"type": "integer"}},
+		"required":   []interface{}{"id"},
+	}
+	page := map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"data": map[string]interface{}{"type": "array", "items": item},
+			"meta": map[string]interface{}{"type": "object"},
+		},
+		"required": []interface{}{"data", "meta"},
+	}
+	pagination := config.PaginationConfig{Type: "cursor", Items: "$.data", CursorPath: "$.meta.next", CursorParam: "after"}
+	cursorMode := pagination
+	cursorMode.Mode = "cursor"
+	mcpBridge := pagedBridge(t, server.URL,
+		config.CustomEndpoint{Name: "all", Method: "GET", Path: "/cursor", OutputSchema: page, Pagination: &pagination},
+		config.CustomEndpoint{Name: "page", Method: "GET", Path: "/cursor", OutputSchema: page, Pagination: &cursorMode},
+	)
+
+	schemas := make(map[string]map[string]interface{})
+	for _, tool := range mcpBridge.Tools() {
+		schemas[tool.Name] = tool.OutputSchema.(map[string]interface{})
+	}
+	assert.Equal(t, map[string]interface{}{
+		"type":       "object",
+		"properties": map[string]interface{}{"result": map[string]interface{}{"type": "array", "items": item}},
+		"required":   []string{"result"},
+	}, schemas["paged__all"])
+	assert.Equal(t, page, schemas["paged__page"])
+
+	for _, name := range []string{"paged__all", "paged__page"} {
+		result := callPaged(t, mcpBridge, name, nil)
+		require.False(t, result.IsError, result.Content[0].Text)
+		assertMatchesSchema(t, schemas[name], result.StructuredContent, name)
+	}
+}
+
+// assertMatchesSchema checks the types, required properties and array items
+// of value against a JSON schema
+func assertMatchesSchema(t *testing.T, schema map[string]interface{}, value interface{}, path string) {
+	t.Helper()
+
+	switch schema["type"] {
+	case "object":
+		object, ok := value.(map[string]interface{})
+		require.True(t, ok, "%s: want an object, got %T", path, value)
+		var required []interface{}
+		switch r := schema["required"].(type) {
+		case []string:
+			for _, name := range r {
+				required = append(required, name)
+			}
+		case []interface{}:
+			required = r
+		}
+		for _, name := range required {
+			assert.Contains(t, object, name, "%s: missing required property", path)
+		}
+		properties, _ := schema["properties"].(map[string]interface{})
+		for name, property := range properties {
+			if v, ok := object[name]; ok {
+				assertMatchesSchema(t, property.(map[string]interface{}), v, path+"."+name)
+			}
+		}
+	case "array":
+		array, ok := value.([]interface{})
+		require.True(t, ok, "%s: want an array, got %T", path, value)
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			for i, v := range array {
+				assertMatchesSchema(t, items, v, fmt.Sprintf("%s[%d]", path, i))
+			}
+		}
+	case "integer":
+		number, ok := value.(float64)
+		assert.True(t, ok && number == float64(int64(number)), "%s: want an integer, got %v", path, value)
+	}
+}
@@ -0,0 +1,175 @@
+package bridge_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"mcp-bridge/internal/bridge"
+	"mcp-bridge/internal/config"
+	"mcp-bridge/pkg/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const reloadConfigTemplate = `{
+  "apis": [
+    {
+      "name": "users",
+      "baseUrl": "http://localhost:8081",
+      "endpoints": [%s]
+    }
+  ]
+}`
+
+func writeReloadConfig(t *testing.T, configPath string, endpoints ...string) {
+	t.Helper()
+	var items []string
+	for _, name := range endpoints {
+		items = append(items, fmt.Sprintf(`{"name": %q, "method": "GET", "path": "/%s"}`, name, name))
+	}
+	list := strings.Join(items, ",")
+	data := []byte(fmt.Sprintf(reloadConfigTemplate, list))
+	require.NoError(t, os.WriteFile(configPath, data, 0644))
+}
+
+func loadValidated(configPath string) func() (*config.Config, error) {
+	return func() (*config.Config, error) {
+		cfg, err := config.LoadConfig(configPath)
+		if err != nil {
+			return nil, err
+		}
+		if err := cfg.Validate(); err != nil {
+			return nil, err
+		}
+		return cfg, nil
+	}
+}
+
+func toolNames(t *testing.T, tr *channelTransport, id int) []string {
+	t.Helper()
+	tr.in <- &types.JSONRPCMessage{JSONRpc: "2.0", ID: id, Method: "tools/list"}
+	var names []string
+	for _, tool := range tr.expect(t).Result.(types.ToolsListResult).Tools {
+		names = append(names, tool.Name)
+	}
+	return names
+}
+
+// startInitialized starts the bridge and completes the client handshake
+func startInitialized(t *testing.T, mcpBridge *bridge.MCPBridge, tr *channelTransport) chan error {
+	t.Helper()
+	done := make(chan error)
+	go func() { done <- mcpBridge.Start() }()
+
+	tr.in <- &types.JSONRPCMessage{JSONRpc: "2.0", Method: "notifications/initialized"}
+	tr.in <- &types.JSONRPCMessage{JSONRpc: "2.0", ID: 0, Method: "ping"}
+	tr.expect(t)
+	return done
+}
+
+func TestReloader_ReloadSwapsTools(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	writeReloadConfig(t, configPath, "list")
+
+	cfg, err := loadValidated(configPath)()
+	require.NoError(t, err)
+
+	tr := newChannelTransport()
+	mcpBridge := bridge.NewMCPBridge(tr)
+	require.NoError(t, mcpBridge.ApplyConfig(cfg))
+	done := startInitialized(t, mcpBridge, tr)
+
+	assert.Equal(t, []string{"users__list"}, toolNames(t, tr, 1))
+
+	reloader := bridge.NewReloader(mcpBridge, cfg, loadValidated(configPath))
+	writeReloadConfig(t, configPath, "list", "get")
+	require.NoError(t, reloader.Reload())
+
+	assert.Equal(t, "notifications/tools/list_changed", tr.expect(t).Method)
+	assert.Equal(t, []string{"users__list", "users__get"}, toolNames(t, tr, 2))
+
+	// An invalid configuration is rejected and the previous tools stay
+	require.NoError(t, os.WriteFile(configPath, []byte(`{"apis": [{"name": "users"}]}`), 0644))
+	require.Error(t, reloader.Reload())
+	assert.Equal(t, []string{"users__list", "users__get"}, toolNames(t, tr, 3))
+
+	close(tr.in)
+	require.NoError(t, <-done)
+	assert.Empty(t, tr.out)
+}
+
+// notifyFailingTransport fails to send notifications
+type notifyFailingTransport struct {
+	*channelTransport
+}
+
+func (t notifyFailingTransport) WriteMessage(msg *types.JSONRPCMessage) error {
+	if msg.Method != "" {
+		return errors.New("client went away")
+	}
+	return t.channelTransport.WriteMessage(msg)
+}
+
+func TestReloader_NotificationFailureKeepsNewConfig(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	writeReloadConfig(t, configPath, "list")
+
+	cfg, err := loadValidated(configPath)()
+	require.NoError(t, err)
+
+	tr := newChannelTransport()
+	mcpBridge := bridge.NewMCPBridge(notifyFailingTransport{tr})
+	require.NoError(t, mcpBridge.ApplyConfig(cfg))
+	done := startInitialized(t, mcpBridge, tr)
+
+	writeReloadConfig(t, configPath, "list", "get")
+	require.NoError(t, bridge.NewReloader(mcpBridge, cfg, loadValidated(configPath)).Reload())
+	assert.Equal(t, []string{"users__list", "users__get"}, toolNames(t, tr, 1))
+
+	err = mcpBridge.ApplyConfig(cfg)
+	require.ErrorContains(t, err, "configuration applied, but notifying clients of the tool list change failed: client went away")
+	assert.Equal(t, []string{"users__list"}, toolNames(t, tr, 2))
+
+	close(tr.in)
+	require.NoError(t, <-done)
+}
+
+func TestReloader_WatchDetectsFileChanges(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	writeReloadConfig(t, configPath, "list")
+
+	cfg, err := loadValidated(configPath)()
+	require.NoError(t, err)
+
+	tr := newChannelTransport()
+	mcpBridge := bridge.NewMCPBridge(tr)
+	require.NoError(t, mcpBridge.ApplyConfig(cfg))
+	done := startInitialized(t, mcpBridge, tr)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	watching := make(chan struct{})
+	go func() {
+		bridge.NewReloader(mcpBridge, cfg, loadValidated(configPath)).Watch(ctx, 10*time.Millisecond)
+		close(watching)
+	}()
+
+	// Make sure the modification time differs on coarse-grained filesystems
+	time.Sleep(20 * time.Millisecond)
+	writeReloadConfig(t, configPath, "search")
+	require.NoError(t, os.Chtimes(configPath, time.Now().Add(time.Second), time.Now().Add(time.Second)))
+
+	assert.Equal(t, "notifications/tools/list_changed", tr.expect(t).Method)
+	assert.Equal(t, []string{"users__search"}, toolNames(t, tr, 1))
+
+	cancel()
+	<-watching
+	close(tr.in)
+	require.NoError(t, <-done)
+}
@@ -0,0 +1,223 @@
+package bridge_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"mcp-bridge/internal/bridge"
+	"mcp-bridge/internal/config"
+	"mcp-bridge/pkg/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const ordersResponse = `{
+  "data": {
+    "orders": [
+      {"id": 1, "status": "open", "customer": {"name": "Jane", "email": "jane@example.com"}, "lines": [{"sku": "A", "price": 5}], "internal": "x"},
+      {"id": 2, "status": "paid", "customer": {"name": "John", "email": "john@example.com"}, "lines": [], "internal": "y"},
+      {"id": 3, "status": "open", "customer": {"name": "Ann", "email": "ann@example.com"}, "lines": [], "internal": "z"}
+    ]
+  }
+}`
+
+func callWithResponseConfig(t *testing.T, response *config.ResponseConfig) (string, map[string]interface{}) {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(ordersResponse))
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{APIs: []config.APIConfig{{
+		Name:      "shop",
+		BaseURL:   server.URL,
+		Endpoints: []config.CustomEndpoint{{Name: "orders", Method: "GET", Path: "/orders", Response: response}},
+	}}}
+	require.NoError(t, cfg.Validate())
+	mcpBridge, err := bridge.NewFromConfig(nil, cfg)
+	require.NoError(t, err)
+
+	result, err := mcpBridge.CallTool(context.Background(), "shop__orders", nil)
+	require.NoError(t, err)
+	require.False(t, result.IsError, result.Content[0].Text)
+	return result.Content[0].Text, result.StructuredContent
+}
+
+func TestMCPBridge_ResponseProjection(t *testing.T) {
+	text, structured := callWithResponseConfig(t, &config.ResponseConfig{
+		Select:   "$.data.orders",
+		MaxItems: 2,
+		Fields:   []string{"id", "status", "customer.name", "lines.sku"},
+		Rename:   map[string]string{"status": "state"},
+	})
+
+	assert.Contains(t, text, "Status: 200\nNote: showing 2 of 3 items\n\nResponse:\n")
+	assert.NotContains(t, text, "email")
+	assert.NotContains(t, text, "internal")
+	assert.Equal(t, map[string]interface{}{"result": []interface{}{
+		map[string]interface{}{
+			"id": float64(1), "state": "open",
+			"customer": map[string]interface{}{"name": "Jane"},
+			"lines":    []interface{}{map[string]interface{}{"sku": "A"}},
+		},
+		map[string]interface{}{
+			"id": float64(2), "state": "paid",
+			"customer": map[string]interface{}{"name": "John"},
+			"lines":    []interface{}{},
+		},
+	}}, structured)
+}
+
+func TestMCPBridge_ResponseExclude(t *testing.T) {
+	_, structured := callWithResponseConfig(t, &config.ResponseConfig{
+		Select:  "data.orders[0]",
+		Exclude: []string{"internal", "customer.email", "lines"},
+	})
+
+	assert.Equal(t, map[string]interface{}{
+		"id": float64(1), "status": "open",
+		"customer": map[string]interface{}{"name": "Jane"},
+	}, structured)
+}
+
+func TestMCPBridge_ResponseSelectWithoutMatch(t *testing.T) {
+	text, structured := callWithResponseConfig(t, &config.ResponseConfig{Select: "$.data.missing"})
+	assert.Equal(t, "Status: 200\n\nResponse:\nnull", text)
+	assert.Nil(t, structured)
+}
+
+func callWithLimits(t *testing.T, body string, limits *config.LimitsConfig) *types.CallToolResult {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{
+		APIs: []config.APIConfig{{
+			Name:      "shop",
+			BaseURL:   server.URL,
+			Endpoints: []config.CustomEndpoint{{Name: "orders", Method: "GET", Path: "/orders"}},
+		}},
+		Server: config.ServerConfig{Limits: limits},
+	}
+	require.NoError(t, cfg.Validate())
+	mcpBridge, err := bridge.NewFromConfig(nil, cfg)
+	require.NoError(t, err)
+
+	result, err := mcpBridge.CallTool(context.Background(), "shop__orders", nil)
+	require.NoError(t, err)
+	return result
+}
+
+func TestMCPBridge_ResponseErrorMasksSecrets(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(ordersResponse))
+	}))
+	defer server.Close()
+
+	mcpBridge := bridge.NewMCPBridge(newChannelTransport())
+	mcpBridge.SetMasker(config.NewMasker([]string{"super-secret-key"}))
+	mcpBridge.AddCustomEndpoint(bridge.APIEndpoint{
+		Name:     "orders",
+		Method:   "GET",
+		Path:     "/orders",
+		BaseURL:  server.URL,
+		Response: &config.ResponseConfig{Select: "$.super-secret-key["},
+	})
+
+	result, err := mcpBridge.CallTool(context.Background(), "orders", nil)
+	require.NoError(t, err)
+	require.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].Text, "Error transforming response: ")
+	assert.Contains(t, result.Content[0].Text, config.MaskedValue)
+	assert.NotContains(t, result.Content[0].Text, "super-secret-key")
+}
+
+func TestMCPBridge_MaxResponseBytes(t *testing.T) {
+	result := callWithLimits(t, strings.Repeat("x", 101), &config.LimitsConfig{MaxResponseBytes: 100})
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].Text, "response body exceeds the limit of 100 bytes")
+
+	result = callWithLimits(t, strings.Repeat("x", 100), &config.LimitsConfig{MaxResponseBytes: 100})
+	assert.False(t, result.IsError)
+}
+
+func TestMCPBridge_MaxOutputBytesArrays(t *testing.T) {
+	items := make([]string, 2314)
+	for i := range items {
+		items[i] = fmt.Sprintf(`{"id": %d}`, i)
+	}
+	body := `{"total": 2314, "items": [` + strings.Join(items, ",") + `]}`
+
+	result := callWithLimits(t, body, &config.LimitsConfig{MaxOutputBytes: 1000})
+	require.False(t, result.IsError)
+	text := result.Content[0].Text
+	assert.Regexp(t, `Note: items: showing \d+ of 2,314 items\n`, text)
+
+	response := text[strings.Index(text, "Response:\n")+len("Response:\n"):]
+	assert.LessOrEqual(t, len(response), 1000)
+	var data struct {
+		Total int           `json:"total"`
+		Items []interface{} `json:"items"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(response), &data), "the output is still valid JSON")
+	assert.Equal(t, 2314, data.Total)
+	assert.Greater(t, len(data.Items), 10)
+	assert.Len(t, result.StructuredContent["items"], len(data.Items))
+}
+
+func TestMCPBridge_MaxOutputBytesKeepsMostItems(t *testing.T) {
+	// Escaped and multi-byte characters and nested arrays make the formatted
+	// size differ from the length of the values
+	var items []interface{}
+	for i := 0; i < 200; i++ {
+		items = append(items, map[string]interface{}{
+			"id":   i,
+			"name": fmt.Sprintf("<item \"%d\"> & é😀", i),
+			"tags": []interface{}{"a", "b\n"},
+		})
+	}
+	body, err := json.Marshal(items)
+	require.NoError(t, err)
+
+	for _, limit := range []int{500, 1234, 5000} {
+		result := callWithLimits(t, string(body), &config.LimitsConfig{MaxOutputBytes: limit})
+		text := result.Content[0].Text
+		response := text[strings.Index(text, "Response:\n")+len("Response:\n"):]
+		var shown []interface{}
+		require.NoError(t, json.Unmarshal([]byte(response), &shown))
+
+		assert.LessOrEqual(t, len(response), limit)
+		more, err := json.MarshalIndent(items[:len(shown)+1], "", "  ")
+		require.NoError(t, err)
+		assert.Greater(t, len(more), limit, "one more item does not fit in %d bytes", limit)
+	}
+}
+
+func TestMCPBridge_MaxOutputBytesStrings(t *testing.T) {
+	body := `[{"id": 1, "text": "` + strings.Repeat("é", 500) + `"}, {"id": 2, "text": "short"}]`
+
+	result := callWithLimits(t, body, &config.LimitsConfig{MaxOutputBytes: 200})
+	text := result.Content[0].Text
+	assert.Contains(t, text, "Note: showing 1 of 2 items\n")
+	assert.Regexp(t, `Note: 1 strings longer than \d+ characters were shortened\n`, text)
+
+	response := text[strings.Index(text, "Response:\n")+len("Response:\n"):]
+	assert.LessOrEqual(t, len(response), 200)
+	var data []map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(response), &data))
+	require.Len(t, data, 1)
+	assert.True(t, strings.HasSuffix(data[0]["text"].(string), "é…"))
+}
+
+func TestMCPBridge_MaxOutputBytesText(t *testing.T) {
+	result := callWithLimits(t, strings.Repeat("line\n", 100), &config.LimitsConfig{MaxOutputBytes: 50})
+	assert.Equal(t, "Status: 200\nNote: showing the first 50 of 500 bytes\n\nResponse:\n"+strings.Repeat("line\n", 10), result.Content[0].Text)
+}
@@ -1,12 +1,15 @@
 package bridge_test
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"mcp-bridge/internal/bridge"
+	"mcp-bridge/internal/config"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -122,6 +125,34 @@ func TestRestClient_MakeRequest_POST(t *testing.T) {
 	assert.NotNil(t, resp.Data)
 }
 
+func TestRestClient_MakeRequest_BodyField(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/users/alice", r.URL.Path)
+
+		var body map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, map[string]interface{}{"id": "alice-2"}, body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client := bridge.NewRestClient()
+	endpoint := bridge.APIEndpoint{
+		Name:    "rename-user",
+		Method:  "PUT",
+		Path:    "/users/{id}",
+		BaseURL: server.URL,
+		Parameters: []bridge.APIParameter{
+			{Name: "id", Type: "string", Required: true, In: "path"},
+			{Name: "body_id", Type: "string", In: "body", Field: "id"},
+		},
+	}
+
+	resp, err := client.MakeRequest(endpoint, map[string]interface{}{"id": "alice", "body_id": "alice-2"})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+}
+
 func TestRestClient_MakeRequest_PathParameters(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		assert.Equal(t, "GET", r.Method)
@@ -396,4 +427,90 @@ func TestRestClient_MakeRequest_HeaderPrecedence(t *testing.T) {
 	resp, err := client.MakeRequest(endpoint, map[string]interface{}{})
 	require.NoError(t, err)
 	assert.Equal(t, http.StatusOK, resp.StatusCode)
-}
\ No newline at end of file
+}
+func TestRestClient_MakeRequestWithContext_Cancelled(t *testing.T) {
+	unblock := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-unblock:
+		}
+	}))
+	defer server.Close()
+	defer close(unblock)
+
+	client := bridge.NewRestClient()
+	endpoint := bridge.APIEndpoint{
+		Name:    "slow",
+		Method:  "GET",
+		Path:    "/slow",
+		BaseURL: server.URL,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	resp, err := client.MakeRequestWithContext(ctx, endpoint, map[string]interface{}{})
+	assert.Nil(t, resp)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
+
+func TestRestClient_PerAPITimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(1500 * time.Millisecond)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"ok": true}`))
+	}))
+	defer server.Close()
+
+	client := bridge.NewRestClient()
+	fast := bridge.APIEndpoint{
+		Name:    "lookup",
+		Method:  "GET",
+		Path:    "/report",
+		APIName: "lookup-api",
+		BaseURL: server.URL,
+		Timeout: 1,
+	}
+	slow := bridge.APIEndpoint{
+		Name:    "report",
+		Method:  "GET",
+		Path:    "/report",
+		APIName: "reporting-api",
+		BaseURL: server.URL,
+		Timeout: 5,
+	}
+
+	_, err := client.MakeRequest(fast, map[string]interface{}{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Client.Timeout exceeded")
+
+	resp, err := client.MakeRequest(slow, map[string]interface{}{})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestRestClient_ResponseHeaderTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(1500 * time.Millisecond)
+	}))
+	defer server.Close()
+
+	client := bridge.NewRestClient()
+	endpoint := bridge.APIEndpoint{
+		Name:    "slow-headers",
+		Method:  "GET",
+		Path:    "/",
+		APIName: "slow-api",
+		BaseURL: server.URL,
+		Timeout: 30,
+		Client:  &config.ClientConfig{ResponseHeaderTimeout: 1},
+	}
+
+	_, err := client.MakeRequest(endpoint, map[string]interface{}{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "timeout awaiting response headers")
+}
@@ -0,0 +1,147 @@
+package bridge_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"mcp-bridge/internal/bridge"
+	"mcp-bridge/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newFlakyServer fails the first failures requests with status, then
+// answers {"ok": true}
+func newFlakyServer(t *testing.T, failures, status int, retryAfter string) (*httptest.Server, *int) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests <= failures {
+			if retryAfter != "" {
+				w.Header().Set("Retry-After", retryAfter)
+			}
+			http.Error(w, "try again", status)
+			return
+		}
+		w.Write([]byte(`{"ok": true}`))
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func retryBridge(t *testing.T, baseURL string, retry *config.RetryConfig) *bridge.MCPBridge {
+	t.Helper()
+	cfg := &config.Config{APIs: []config.APIConfig{{
+		Name:    "flaky",
+		BaseURL: baseURL,
+		Retry:   retry,
+		Endpoints: []config.CustomEndpoint{
+			{Name: "get", Method: "GET", Path: "/items"},
+			{Name: "create", Method: "POST", Path: "/items"},
+		},
+	}}}
+	require.NoError(t, cfg.Validate())
+	mcpBridge, err := bridge.NewFromConfig(nil, cfg)
+	require.NoError(t, err)
+	return mcpBridge
+}
+
+func TestMCPBridge_RetrySucceeds(t *testing.T) {
+	server, requests := newFlakyServer(t, 2, http.StatusServiceUnavailable, "")
+	mcpBridge := retryBridge(t, server.URL, &config.RetryConfig{InitialBackoffMs: 1, MaxBackoffMs: 5})
+
+	result, err := mcpBridge.CallTool(context.Background(), "flaky__get", nil)
+	require.NoError(t, err)
+	assert.False(t, result.IsError)
+	assert.Equal(t, 3, *requests)
+	assert.Contains(t, result.Content[0].Text, "Note: succeeded after 3 attempts")
+	assert.Equal(t, map[string]interface{}{"attempts": 3}, result.Meta)
+}
+
+func TestMCPBridge_RetryGivesUp(t *testing.T) {
+	server, requests := newFlakyServer(t, 5, http.StatusBadGateway, "")
+	mcpBridge := retryBridge(t, server.URL, &config.RetryConfig{MaxAttempts: 2, InitialBackoffMs: 1})
+
+	result, err := mcpBridge.CallTool(context.Background(), "flaky__get", nil)
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Equal(t, 2, *requests)
+	assert.Contains(t, result.Content[0].Text, "API Error: HTTP 502")
+	assert.Contains(t, result.Content[0].Text, "(after 2 attempts)")
+	assert.Equal(t, map[string]interface{}{"attempts": 2}, result.Meta)
+}
+
+func TestMCPBridge_RetryOnlyIdempotentMethods(t *testing.T) {
+	server, requests := newFlakyServer(t, 1, http.StatusServiceUnavailable, "")
+	mcpBridge := retryBridge(t, server.URL, &config.RetryConfig{InitialBackoffMs: 1})
+
+	result, err := mcpBridge.CallTool(context.Background(), "flaky__create", nil)
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Equal(t, 1, *requests)
+	assert.Equal(t, map[string]interface{}{"attempts": 1}, result.Meta)
+}
+
+func TestMCPBridge_RetryStatuses(t *testing.T) {
+	server, requests := newFlakyServer(t, 1, http.StatusInternalServerError, "")
+	mcpBridge := retryBridge(t, server.URL, &config.RetryConfig{InitialBackoffMs: 1})
+
+	result, err := mcpBridge.CallTool(context.Background(), "flaky__get", nil)
+	require.NoError(t, err)
+	assert.True(t, result.IsError, "500 is not retried by default")
+	assert.Equal(t, 1, *requests)
+}
+
+func TestMCPBridge_RetryAfter(t *testing.T) {
+	server, requests := newFlakyServer(t, 1, http.StatusTooManyRequests, "0")
+	mcpBridge := retryBridge(t, server.URL, &config.RetryConfig{InitialBackoffMs: 5000})
+
+	start := time.Now()
+	result, err := mcpBridge.CallTool(context.Background(), "flaky__get", nil)
+	require.NoError(t, err)
+	assert.False(t, result.IsError)
+	assert.Equal(t, 2, *requests)
+	assert.Less(t, time.Since(start), 2*time.Second, "Retry-After replaces the backoff")
+
+	// A server asking to wait longer than the maximum backoff is not retried
+	server, requests = newFlakyServer(t, 1, http.StatusTooManyRequests, "3600")
+	mcpBridge = retryBridge(t, server.URL, &config.RetryConfig{InitialBackoffMs: 1})
+
+	result, err = mcpBridge.CallTool(context.Background(), "flaky__get", nil)
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Equal(t, 1, *requests)
+}
+
+func TestMCPBridge_RetryConnectionErrors(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	baseURL := server.URL
+	server.Close()
+	mcpBridge := retryBridge(t, baseURL, &config.RetryConfig{MaxAttempts: 3, InitialBackoffMs: 1})
+
+	result, err := mcpBridge.CallTool(context.Background(), "flaky__get", nil)
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.True(t, strings.HasPrefix(result.Content[0].Text, "Error calling API: "))
+	assert.Contains(t, result.Content[0].Text, "(after 3 attempts)")
+}
+
+func TestMCPBridge_RetryStopsWhenCancelled(t *testing.T) {
+	server, requests := newFlakyServer(t, 5, http.StatusServiceUnavailable, "")
+	mcpBridge := retryBridge(t, server.URL, &config.RetryConfig{InitialBackoffMs: 5000})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	result, err := mcpBridge.CallTool(ctx, "flaky__get", nil)
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].Text, "Tool call timed out")
+	assert.Equal(t, 1, *requests)
+	assert.Less(t, time.Since(start), 2*time.Second)
+}
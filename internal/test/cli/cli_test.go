@@ -0,0 +1,298 @@
+package cli_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"mcp-bridge/internal/cli"
+	"mcp-bridge/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// run executes an mcpify command line and returns its exit code and output
+func run(args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	code := cli.Run(args, &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+func usersConfig(t *testing.T, baseURL string) string {
+	t.Helper()
+	return writeFile(t, "config.json", fmt.Sprintf(`{
+  "apis": [{
+    "name": "users-api",
+    "baseUrl": %q,
+    "endpoints": [
+      {
+        "name": "get_user",
+        "description": "Get a user",
+        "method": "GET",
+        "path": "/users/{id}",
+        "parameters": [{"name": "id", "type": "integer", "required": true, "in": "path"}]
+      },
+      {
+        "name": "list_users",
+        "description": "List users",
+        "method": "GET",
+        "path": "/users"
+      }
+    ]
+  }]
+}`, baseURL))
+}
+
+func TestRun_Usage(t *testing.T) {
+	code, _, stderr := run()
+	assert.Equal(t, 2, code)
+	assert.Contains(t, stderr, "list-tools")
+
+	code, _, stderr = run("frobnicate")
+	assert.Equal(t, 2, code)
+	assert.Contains(t, stderr, `unknown command "frobnicate"`)
+
+	code, stdout, _ := run("help")
+	assert.Equal(t, 0, code)
+	assert.Contains(t, stdout, "serve")
+
+	code, _, stderr = run("call", "--config", "config.json")
+	assert.Equal(t, 2, code, "call needs a tool name")
+	assert.Contains(t, stderr, "Usage: mcpify call")
+}
+
+func TestValidate(t *testing.T) {
+	code, stdout, _ := run("validate", "--config", usersConfig(t, "http://localhost:8081"))
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "Configuration is valid: 1 APIs, 2 tools\n", stdout)
+
+	invalid := writeFile(t, "config.json", `{
+  "apis": [{"name": "users-api", "endpoints": [{"name": "get_user", "path": "/users"}]}]
+}`)
+	code, _, stderr := run("validate", "--config", invalid)
+	assert.Equal(t, 1, code)
+	assert.Contains(t, stderr, "API users-api: base URL is required")
+	assert.Contains(t, stderr, "API users-api, endpoint get_user: method is required")
+}
+
+func TestValidate_StrictAppliesOverridesFirst(t *testing.T) {
+	configPath := writeFile(t, "config.json", `{
+  "apis": [{"name": "users-api", "endpoints": [{"name": "list_users", "method": "GET", "path": "/users"}]}]
+}`)
+
+	code, stdout, stderr := run("validate", "--config", configPath, "--strict", "--api-url", "http://localhost:8081")
+	assert.Equal(t, 0, code, stderr)
+	assert.Equal(t, "Configuration is valid: 1 APIs, 1 tools\n", stdout)
+
+	code, _, stderr = run("validate", "--config", configPath, "--strict")
+	assert.Equal(t, 1, code)
+	assert.Contains(t, stderr, "API users-api: base URL is required")
+
+	typo := writeFile(t, "config.json", `{
+  "apis": [{"name": "users-api", "base_url": "http://localhost:8081"}]
+}`)
+	code, _, stderr = run("validate", "--config", typo, "--strict", "--api-url", "http://localhost:8081")
+	assert.Equal(t, 1, code)
+	assert.Contains(t, stderr, "apis[0].base_url: unknown field, did you mean 'baseUrl'?")
+}
+
+func TestListTools(t *testing.T) {
+	configPath := usersConfig(t, "http://localhost:8081")
+
+	code, stdout, _ := run("list-tools", "--config", configPath)
+	require.Equal(t, 0, code)
+	assert.Contains(t, stdout, "users-api__get_user")
+	assert.Contains(t, stdout, "Get a user (GET /users/{id})")
+
+	code, stdout, _ = run("list-tools", "--config", configPath, "--json")
+	require.Equal(t, 0, code)
+	var listed struct {
+		Tools []struct {
+			Name string `json:"name"`
+		} `json:"tools"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(stdout), &listed))
+	require.Len(t, listed.Tools, 2)
+	assert.Equal(t, "users-api__get_user", listed.Tools[0].Name)
+}
+
+func TestListTools_ReadOnly(t *testing.T) {
+	configPath := writeFile(t, "config.json", `{
+  "apis": [{
+    "name": "users-api",
+    "baseUrl": "http://localhost:8081",
+    "endpoints": [
+      {"name": "list_users", "method": "GET", "path": "/users"},
+      {"name": "delete_user", "method": "DELETE", "path": "/users"}
+    ]
+  }]
+}`)
+
+	code, stdout, _ := run("list-tools", "--config", configPath, "--read-only")
+	require.Equal(t, 0, code)
+	assert.Contains(t, stdout, "users-api__list_users")
+	assert.NotContains(t, stdout, "users-api__delete_user")
+
+	code, stdout, _ = run("list-tools", "--config", configPath, "--allowed-methods", "DELETE")
+	require.Equal(t, 0, code)
+	assert.NotContains(t, stdout, "users-api__list_users")
+	assert.Contains(t, stdout, "users-api__delete_user")
+
+	code, _, stderr := run("validate", "--config", configPath, "--allowed-methods", "GET,FETCH")
+	assert.Equal(t, 1, code)
+	assert.Contains(t, stderr, "unknown HTTP method 'FETCH'")
+}
+
+func TestCall(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"path": %q}`, r.URL.Path)
+	}))
+	defer server.Close()
+
+	code, stdout, _ := run("call", "--config", usersConfig(t, server.URL), "users-api__get_user", `{"id": 7}`)
+	assert.Equal(t, 0, code)
+	assert.Contains(t, stdout, "GET "+server.URL+"/users/7\n")
+	assert.Contains(t, stdout, "Status: 200")
+	assert.Contains(t, stdout, `"path": "/users/7"`)
+
+	code, _, stderr := run("call", "--config", usersConfig(t, server.URL), "users-api__get_user", `[7]`)
+	assert.Equal(t, 1, code)
+	assert.Contains(t, stderr, "arguments must be a JSON object")
+}
+
+func TestCall_ArgumentsAndMasking(t *testing.T) {
+	var received map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{"id": 1}`)
+	}))
+	defer server.Close()
+
+	configPath := writeFile(t, "config.json", fmt.Sprintf(`{
+  "apis": [{
+    "name": "users-api",
+    "baseUrl": %q,
+    "auth": [{"type": "bearer", "bearer": {"token": "s3cr3t-token"}}],
+    "headers": {"X-API-Key": "k3y-value"},
+    "endpoints": [{
+      "name": "create_user",
+      "method": "POST",
+      "path": "/users",
+      "parameters": [
+        {"name": "name", "in": "body"},
+        {"name": "age", "type": "integer", "in": "body"},
+        {"name": "tags", "type": "array", "in": "body"}
+      ]
+    }]
+  }]
+}`, server.URL))
+	argsFile := writeFile(t, "args.json", `{"name": "from file", "age": 30}`)
+
+	code, stdout, _ := run("call", "--config", configPath, "--args-file", argsFile,
+		"--arg", "name=Jane Doe", "--arg", `tags:=["a","b"]`, "users-api__create_user")
+	require.Equal(t, 0, code)
+
+	assert.Equal(t, map[string]interface{}{"name": "Jane Doe", "age": float64(30), "tags": []interface{}{"a", "b"}}, received)
+	assert.Contains(t, stdout, "POST "+server.URL+"/users\n")
+	assert.Contains(t, stdout, "Authorization: Bearer ****\n")
+	assert.Contains(t, stdout, "X-Api-Key: ****\n")
+	assert.Contains(t, stdout, `"name":"Jane Doe"`)
+	assert.NotContains(t, stdout, "s3cr3t-token")
+	assert.NotContains(t, stdout, "k3y-value")
+	assert.Contains(t, stdout, "Status: 201")
+
+	code, _, stderr := run("call", "--config", configPath, "--arg", "name", "users-api__create_user")
+	assert.Equal(t, 1, code)
+	assert.Contains(t, stderr, `invalid -arg "name"`)
+}
+
+func TestCall_ExitCodeReflectsIsError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error": "not found"}`, http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	code, stdout, stderr := run("call", "--config", usersConfig(t, server.URL), "--quiet", "--json", "--arg", "id=404", "users-api__get_user")
+	assert.Equal(t, 1, code)
+	assert.Empty(t, stderr)
+	assert.NotContains(t, stdout, "GET ", "--quiet omits the request")
+
+	var result struct {
+		IsError bool `json:"isError"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(stdout), &result))
+	assert.True(t, result.IsError)
+
+	code, stdout, _ = run("call", "--config", usersConfig(t, server.URL), "users-api__missing")
+	assert.Equal(t, 1, code)
+	assert.Contains(t, stdout, "Unknown tool: users-api__missing")
+}
+
+func TestCall_DryRun(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request %s %s", r.Method, r.URL)
+	}))
+	defer server.Close()
+
+	code, stdout, _ := run("call", "--config", usersConfig(t, server.URL), "--dry-run", "--arg", "id=7", "users-api__get_user")
+	assert.Equal(t, 0, code)
+	assert.Contains(t, stdout, "Dry run: the request was not sent.")
+	assert.Contains(t, stdout, "GET "+server.URL+"/users/7\n")
+}
+
+const petstoreSpec = `
+openapi: 3.0.3
+info:
+  title: Petstore
+  version: 1.0.0
+servers:
+  - url: https://api.example.com/v1
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      tags: [pets]
+  /store/inventory:
+    get:
+      operationId: getInventory
+      tags: [store]
+`
+
+func TestImport(t *testing.T) {
+	specPath := writeFile(t, "petstore.yaml", petstoreSpec)
+
+	code, stdout, _ := run("import", "--name", "pets", "--exclude-tag", "store", specPath)
+	require.Equal(t, 0, code)
+
+	var cfg config.Config
+	require.NoError(t, json.Unmarshal([]byte(stdout), &cfg))
+	require.Len(t, cfg.APIs, 1)
+	assert.Equal(t, "pets", cfg.APIs[0].Name)
+	assert.Equal(t, "https://api.example.com/v1", cfg.APIs[0].BaseURL)
+	require.Len(t, cfg.APIs[0].Endpoints, 1)
+	assert.Equal(t, "listPets", cfg.APIs[0].Endpoints[0].Name)
+
+	output := filepath.Join(t.TempDir(), "pets.yaml")
+	code, _, stderr := run("import", "-o", output, specPath)
+	require.Equal(t, 0, code, stderr)
+	assert.Contains(t, stderr, "Imported 2 endpoints")
+
+	code, stdout, _ = run("validate", "--config", output, "--strict")
+	assert.Equal(t, 0, code)
+	assert.Contains(t, stdout, "1 APIs, 2 tools")
+}
@@ -158,7 +158,7 @@ func TestConfig_Validate_UnsupportedAuthType(t *testing.T) {
 				Timeout: 30,
 				Auth: []config.AuthConfig{
 					{
-						Type: "oauth2",
+						Type: "digest",
 					},
 				},
 			},
@@ -171,7 +171,7 @@ func TestConfig_Validate_UnsupportedAuthType(t *testing.T) {
 
 	err := cfg.Validate()
 	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "unsupported auth type 'oauth2'")
+	assert.Contains(t, err.Error(), "unsupported auth type 'digest'")
 }
 
 func TestConfig_Validate_NoAuth(t *testing.T) {
@@ -251,4 +251,110 @@ func TestConfig_Validate_HeadersWithAuth(t *testing.T) {
 	err := cfg.Validate()
 	assert.NoError(t, err)
 	assert.Equal(t, "test-key", cfg.APIs[0].Headers["X-API-Key"])
-}
\ No newline at end of file
+}
+
+func TestConfig_Validate_BearerAuth(t *testing.T) {
+	cfg := &config.Config{
+		APIs: []config.APIConfig{
+			{
+				Name:    "test-api",
+				BaseURL: "http://localhost:8080",
+				Auth: []config.AuthConfig{
+					{
+						Type:   "bearer",
+						Bearer: &config.BearerAuthConfig{Token: "secret"},
+					},
+				},
+			},
+		},
+	}
+	assert.NoError(t, cfg.Validate())
+
+	cfg.APIs[0].Auth[0].Bearer.Token = ""
+	err := cfg.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "bearer auth token is required")
+
+	cfg.APIs[0].Auth[0].Bearer = nil
+	err = cfg.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "bearer auth configuration is required")
+}
+
+func TestConfig_Validate_APIKeyAuth(t *testing.T) {
+	cfg := &config.Config{
+		APIs: []config.APIConfig{
+			{
+				Name:    "test-api",
+				BaseURL: "http://localhost:8080",
+				Auth: []config.AuthConfig{
+					{
+						Type:   "apiKey",
+						APIKey: &config.APIKeyAuthConfig{Name: "X-API-Key", Value: "secret"},
+					},
+				},
+			},
+		},
+	}
+	assert.NoError(t, cfg.Validate())
+	assert.Equal(t, "header", cfg.APIs[0].Auth[0].APIKey.In, "location should default to header")
+
+	cfg.APIs[0].Auth[0].APIKey.In = "body"
+	err := cfg.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "apiKey auth location must be 'header', 'query' or 'cookie'")
+
+	cfg.APIs[0].Auth[0].APIKey.In = "query"
+	cfg.APIs[0].Auth[0].APIKey.Value = ""
+	err = cfg.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "apiKey auth value is required")
+
+	cfg.APIs[0].Auth[0].APIKey.Name = ""
+	err = cfg.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "apiKey auth name is required")
+}
+
+func TestConfig_Validate_OAuth2Auth(t *testing.T) {
+	cfg := &config.Config{
+		APIs: []config.APIConfig{
+			{
+				Name:    "test-api",
+				BaseURL: "http://localhost:8080",
+				Auth: []config.AuthConfig{
+					{
+						Type: "oauth2",
+						OAuth2: &config.OAuth2AuthConfig{
+							TokenURL:     "http://localhost:8080/oauth/token",
+							ClientID:     "client",
+							ClientSecret: "secret",
+						},
+					},
+				},
+			},
+		},
+	}
+	assert.NoError(t, cfg.Validate())
+	assert.Equal(t, "basic", cfg.APIs[0].Auth[0].OAuth2.ClientAuth, "client auth should default to basic")
+
+	cfg.APIs[0].Auth[0].OAuth2.ClientAuth = "jwt"
+	err := cfg.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "oauth2 clientAuth must be 'basic' or 'body'")
+
+	cfg.APIs[0].Auth[0].OAuth2.ClientSecret = ""
+	err = cfg.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "oauth2 client secret is required")
+
+	cfg.APIs[0].Auth[0].OAuth2.TokenURL = ""
+	err = cfg.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "oauth2 token URL is required")
+
+	cfg.APIs[0].Auth[0].OAuth2 = nil
+	err = cfg.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "oauth2 auth configuration is required")
+}
@@ -331,6 +331,7 @@ func TestValidate_ServerDefaults(t *testing.T) {
 }
 
 func TestValidate_WithTransportConfig(t *testing.T) {
+	cors := true
 	cfg := &config.Config{
 		APIs: []config.APIConfig{
 			{
@@ -348,7 +349,7 @@ func TestValidate_WithTransportConfig(t *testing.T) {
 			HTTP: &config.HTTPTransportConfig{
 				Host: "localhost",
 				Port: 8080,
-				CORS: true,
+				CORS: &cors,
 			},
 		},
 	}
@@ -358,6 +359,21 @@ func TestValidate_WithTransportConfig(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestLoadConfig_TransportCORSUnset(t *testing.T) {
+	dir := writeTree(t, map[string]string{
+		"config.json": `{
+			"apis": [{"name": "users-api", "baseUrl": "http://localhost:8081"}],
+			"transport": {"type": "http", "http": {"port": 9000}}
+		}`,
+	})
+
+	cfg, err := config.LoadConfig(filepath.Join(dir, "config.json"))
+	require.NoError(t, err)
+	require.NotNil(t, cfg.Transport.HTTP)
+	assert.Equal(t, 9000, cfg.Transport.HTTP.Port)
+	assert.Nil(t, cfg.Transport.HTTP.CORS, "an unset cors keeps the flag default")
+}
+
 func TestValidate_WithoutTransportConfig(t *testing.T) {
 	cfg := &config.Config{
 		APIs: []config.APIConfig{
@@ -376,4 +392,190 @@ func TestValidate_WithoutTransportConfig(t *testing.T) {
 
 	err := cfg.Validate()
 	assert.NoError(t, err)
-}
\ No newline at end of file
+}
+func TestValidate_NegativeClientSettings(t *testing.T) {
+	cfg := &config.Config{
+		APIs: []config.APIConfig{
+			{
+				Name:    "test-api",
+				BaseURL: "http://localhost:8080",
+				Timeout: 30,
+				Client:  &config.ClientConfig{ConnectTimeout: -1},
+			},
+		},
+	}
+
+	err := cfg.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "client timeouts must not be negative")
+
+	cfg.APIs[0].Client = &config.ClientConfig{ConnectTimeout: 5, MaxConnsPerHost: -2}
+	err = cfg.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "client connection limits must not be negative")
+}
+
+func TestValidate_AllowedMethods(t *testing.T) {
+	cfg := &config.Config{
+		APIs: []config.APIConfig{
+			{
+				Name:           "test-api",
+				BaseURL:        "http://localhost:8080",
+				AllowedMethods: []string{"get", "HEAD"},
+			},
+		},
+		Server: config.ServerConfig{AllowedMethods: []string{"GET"}},
+	}
+	assert.NoError(t, cfg.Validate())
+
+	cfg.APIs[0].AllowedMethods = []string{"GET", "FETCH"}
+	cfg.Server.AllowedMethods = []string{"READ"}
+	err := cfg.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "API test-api: unknown HTTP method 'FETCH' in allowedMethods")
+	assert.Contains(t, err.Error(), "server: unknown HTTP method 'READ' in allowedMethods")
+}
+
+func TestMethodAllowed(t *testing.T) {
+	assert.True(t, config.MethodAllowed(nil, "DELETE"), "an empty list allows every method")
+	assert.True(t, config.MethodAllowed([]string{"GET", "HEAD"}, "get"))
+	assert.False(t, config.MethodAllowed([]string{"GET", "HEAD"}, "POST"))
+	assert.True(t, config.IsSafeMethod("OPTIONS"))
+	assert.False(t, config.IsSafeMethod("PATCH"))
+}
+
+func TestValidate_ResponseConfig(t *testing.T) {
+	cfg := &config.Config{
+		APIs: []config.APIConfig{
+			{
+				Name:    "test-api",
+				BaseURL: "http://localhost:8080",
+				Endpoints: []config.CustomEndpoint{
+					{Name: "list", Method: "GET", Path: "/items", Response: &config.ResponseConfig{
+						Select:   "$.items[*]",
+						MaxItems: 10,
+						Fields:   []string{"id", "owner.name"},
+						Rename:   map[string]string{"id": "itemId"},
+					}},
+				},
+			},
+		},
+	}
+	assert.NoError(t, cfg.Validate())
+
+	cfg.APIs[0].Endpoints[0].Response = &config.ResponseConfig{
+		Select:   "$.items[",
+		MaxItems: -1,
+		Exclude:  []string{"owner..name"},
+		Rename:   map[string]string{"id": ""},
+	}
+	err := cfg.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "API test-api, endpoint list: response select: invalid path")
+	assert.Contains(t, err.Error(), "response maxItems must not be negative")
+	assert.Contains(t, err.Error(), "invalid response field 'owner..name'")
+	assert.Contains(t, err.Error(), "response rename must map a field to a non-empty name")
+}
+
+func TestValidate_NegativeLimits(t *testing.T) {
+	cfg := &config.Config{
+		APIs: []config.APIConfig{
+			{
+				Name:    "test-api",
+				BaseURL: "http://localhost:8080",
+				Limits:  &config.LimitsConfig{MaxOutputBytes: -1},
+			},
+		},
+		Server: config.ServerConfig{Limits: &config.LimitsConfig{MaxResponseBytes: -1}},
+	}
+
+	err := cfg.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "API test-api: limits must not be negative")
+	assert.Contains(t, err.Error(), "server limits must not be negative")
+}
+
+func TestValidate_Pagination(t *testing.T) {
+	pagination := &config.PaginationConfig{Type: "page"}
+	cfg := &config.Config{
+		APIs: []config.APIConfig{
+			{
+				Name:    "test-api",
+				BaseURL: "http://localhost:8080",
+				Endpoints: []config.CustomEndpoint{
+					{Name: "list", Method: "GET", Path: "/items", Pagination: pagination},
+				},
+			},
+		},
+	}
+	require.NoError(t, cfg.Validate())
+	assert.Equal(t, "page", pagination.PageParam)
+	assert.Equal(t, "aggregate", pagination.Mode)
+	assert.Equal(t, config.DefaultMaxPages, pagination.MaxPages)
+
+	cfg.APIs[0].Endpoints[0].Pagination = &config.PaginationConfig{Type: "cursor", Mode: "stream", Items: "$.[", MaxItems: -1}
+	err := cfg.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "API test-api, endpoint list: pagination type 'cursor' requires cursorPath and cursorParam")
+	assert.Contains(t, err.Error(), "pagination mode must be 'aggregate' or 'cursor', got 'stream'")
+	assert.Contains(t, err.Error(), "pagination invalid path")
+	assert.Contains(t, err.Error(), "pagination maxPages and maxItems must not be negative")
+
+	cfg.APIs[0].Endpoints[0].Pagination = &config.PaginationConfig{Type: "scroll"}
+	assert.ErrorContains(t, cfg.Validate(), "pagination type must be 'link', 'cursor', 'page' or 'offset', got 'scroll'")
+
+	// A "cursor" parameter is an ordinary parameter in aggregate mode but
+	// conflicts with the argument cursor mode adds
+	cfg.APIs[0].Endpoints[0].Parameters = []config.CustomParameter{{Name: "cursor"}}
+	cfg.APIs[0].Endpoints[0].Pagination = &config.PaginationConfig{Type: "cursor", CursorPath: "$.next", CursorParam: "cursor"}
+	require.NoError(t, cfg.Validate())
+	cfg.APIs[0].Endpoints[0].Pagination = &config.PaginationConfig{Type: "cursor", Mode: "cursor", CursorPath: "$.next", CursorParam: "cursor"}
+	assert.ErrorContains(t, cfg.Validate(), "API test-api, endpoint list: pagination parameter 'cursor' conflicts with the tool argument that mode 'cursor' adds")
+}
+
+func TestValidate_Retry(t *testing.T) {
+	retry := &config.RetryConfig{InitialBackoffMs: 20000}
+	cfg := &config.Config{
+		APIs: []config.APIConfig{
+			{Name: "test-api", BaseURL: "http://localhost:8080", Retry: retry},
+		},
+	}
+	require.NoError(t, cfg.Validate())
+	assert.Equal(t, config.DefaultMaxAttempts, retry.MaxAttempts)
+	assert.Equal(t, 20000, retry.MaxBackoffMs)
+	assert.Equal(t, config.DefaultRetryStatuses, retry.Statuses)
+	assert.Equal(t, config.IdempotentMethods, retry.Methods)
+	assert.True(t, retry.RetriesStatus(503))
+	assert.False(t, retry.RetriesStatus(500))
+
+	// The defaults are copied, so changing an API's retry settings leaves
+	// them alone
+	retry.Statuses[0] = 500
+	retry.Methods[0] = "POST"
+	assert.Equal(t, 429, config.DefaultRetryStatuses[0])
+	assert.Equal(t, "GET", config.IdempotentMethods[0])
+
+	cfg.APIs[0].Retry = &config.RetryConfig{MaxAttempts: -1, InitialBackoffMs: 100, MaxBackoffMs: 50, Statuses: []int{42}, Methods: []string{"FETCH"}}
+	err := cfg.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "API test-api: retry maxAttempts, initialBackoffMs and maxBackoffMs must not be negative")
+	assert.Contains(t, err.Error(), "retry invalid status 42 in statuses")
+	assert.Contains(t, err.Error(), "retry unknown HTTP method 'FETCH' in methods")
+	assert.Contains(t, err.Error(), "retry maxBackoffMs must not be less than initialBackoffMs")
+}
+
+func TestValidate_CircuitBreaker(t *testing.T) {
+	breaker := &config.CircuitBreakerConfig{}
+	cfg := &config.Config{
+		APIs: []config.APIConfig{
+			{Name: "test-api", BaseURL: "http://localhost:8080", CircuitBreaker: breaker},
+		},
+	}
+	require.NoError(t, cfg.Validate())
+	assert.Equal(t, config.DefaultFailureThreshold, breaker.FailureThreshold)
+	assert.Equal(t, config.DefaultOpenSeconds, breaker.OpenSeconds)
+	assert.Equal(t, config.DefaultHalfOpenProbes, breaker.HalfOpenProbes)
+
+	cfg.APIs[0].CircuitBreaker = &config.CircuitBreakerConfig{OpenSeconds: -1}
+	assert.ErrorContains(t, cfg.Validate(), "API test-api: circuitBreaker failureThreshold, openSeconds and halfOpenProbes must not be negative")
+}
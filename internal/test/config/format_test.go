@@ -0,0 +1,159 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"mcp-bridge/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const yamlConfig = `# Users API catalog
+apis:
+  - name: users-api
+    baseUrl: http://localhost:8081
+    timeout: 15
+    headers:
+      X-API-Key: ${USERS_API_KEY:-dev-key}
+    endpoints:
+      - name: get_user
+        description: |
+          Get a single user.
+          Returns 404 if the user does not exist.
+        method: GET
+        path: /users/{id}
+        parameters:
+          - name: id
+            type: integer
+            required: true
+            in: path
+server:
+  name: yaml-server
+  version: 1.0.0
+`
+
+const tomlConfig = `# Users API catalog
+[server]
+name = "toml-server"
+version = "1.0.0"
+
+[[apis]]
+name = "users-api"
+baseUrl = "http://localhost:8081"
+timeout = 15
+
+  [apis.headers]
+  X-API-Key = "${USERS_API_KEY:-dev-key}"
+
+  [[apis.endpoints]]
+  name = "get_user"
+  description = """
+Get a single user.
+Returns 404 if the user does not exist.
+"""
+  method = "GET"
+  path = "/users/{id}"
+
+    [[apis.endpoints.parameters]]
+    name = "id"
+    type = "integer"
+    required = true
+    in = "path"
+`
+
+func writeConfigFile(t *testing.T, name, content string) string {
+	t.Helper()
+	configPath := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(configPath, []byte(content), 0644))
+	return configPath
+}
+
+func assertUsersAPI(t *testing.T, cfg *config.Config) {
+	t.Helper()
+	require.Len(t, cfg.APIs, 1)
+	api := cfg.APIs[0]
+	assert.Equal(t, "users-api", api.Name)
+	assert.Equal(t, 15, api.Timeout)
+	assert.Equal(t, "dev-key", api.Headers["X-API-Key"])
+	require.Len(t, api.Endpoints, 1)
+	assert.Equal(t, "Get a single user.\nReturns 404 if the user does not exist.\n", api.Endpoints[0].Description)
+	require.Len(t, api.Endpoints[0].Parameters, 1)
+	assert.True(t, api.Endpoints[0].Parameters[0].Required)
+	assert.Equal(t, "path", api.Endpoints[0].Parameters[0].In)
+}
+
+func TestLoadConfig_YAML(t *testing.T) {
+	for _, name := range []string{"config.yaml", "config.yml"} {
+		cfg, err := config.LoadConfig(writeConfigFile(t, name, yamlConfig))
+		require.NoError(t, err, name)
+		assertUsersAPI(t, cfg)
+		assert.Equal(t, "yaml-server", cfg.Server.Name)
+	}
+}
+
+func TestLoadConfig_TOML(t *testing.T) {
+	cfg, err := config.LoadConfig(writeConfigFile(t, "config.toml", tomlConfig))
+	require.NoError(t, err)
+	assertUsersAPI(t, cfg)
+	assert.Equal(t, "toml-server", cfg.Server.Name)
+}
+
+func TestLoadConfigWithFormat(t *testing.T) {
+	configPath := writeConfigFile(t, "catalog.conf", yamlConfig)
+
+	_, err := config.LoadConfig(configPath)
+	assert.Error(t, err, "files without a known extension are read as JSON")
+
+	cfg, err := config.LoadConfigWithFormat(configPath, "yaml")
+	require.NoError(t, err)
+	assertUsersAPI(t, cfg)
+
+	_, err = config.LoadConfigWithFormat(configPath, "ini")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unsupported config format 'ini'")
+}
+
+func TestSaveConfig_RoundTrip(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"config.yaml", yamlConfig},
+		{"config.toml", tomlConfig},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configPath := writeConfigFile(t, tt.name, tt.content)
+			cfg, err := config.LoadConfig(configPath)
+			require.NoError(t, err)
+
+			require.NoError(t, config.SaveConfig(cfg, configPath))
+
+			data, err := os.ReadFile(configPath)
+			require.NoError(t, err)
+			assert.Contains(t, string(data), "${USERS_API_KEY:-dev-key}", "references should be saved unexpanded")
+			assert.NotContains(t, string(data), "{\n", "output should not be JSON")
+
+			reloaded, err := config.LoadConfig(configPath)
+			require.NoError(t, err)
+			assertUsersAPI(t, reloaded)
+			assert.Equal(t, cfg.Server, reloaded.Server)
+		})
+	}
+}
+
+func TestSaveConfig_KeepsLoadedFormat(t *testing.T) {
+	configPath := writeConfigFile(t, "catalog.conf", tomlConfig)
+	cfg, err := config.LoadConfigWithFormat(configPath, "toml")
+	require.NoError(t, err)
+
+	require.NoError(t, config.SaveConfig(cfg, configPath))
+
+	reloaded, err := config.LoadConfigWithFormat(configPath, "toml")
+	require.NoError(t, err)
+	assertUsersAPI(t, reloaded)
+}
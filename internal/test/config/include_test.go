@@ -0,0 +1,178 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"mcp-bridge/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeTree writes files relative to a new temporary directory and returns it
+func writeTree(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+	return dir
+}
+
+func apiNames(cfg *config.Config) []string {
+	var names []string
+	for _, api := range cfg.APIs {
+		names = append(names, api.Name)
+	}
+	return names
+}
+
+func TestLoadConfig_Include(t *testing.T) {
+	dir := writeTree(t, map[string]string{
+		"config.json": `{
+			"include": ["apis.d/*.json", "apis.d/*.yaml"],
+			"apis": [{"name": "root-api", "baseUrl": "http://localhost:8080"}]
+		}`,
+		"apis.d/orders.json": `{"name": "orders-api", "baseUrl": "http://localhost:8082"}`,
+		"apis.d/users.yaml":  "name: users-api\nbaseUrl: http://localhost:8081\n",
+		"apis.d/team.json": `{
+			"include": ["nested/*.json"],
+			"apis": [{"name": "team-api", "baseUrl": "http://localhost:8083"}]
+		}`,
+		"apis.d/nested/billing.json": `{"name": "billing-api", "baseUrl": "http://localhost:8084"}`,
+	})
+
+	cfg, err := config.LoadConfig(filepath.Join(dir, "config.json"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"root-api", "orders-api", "team-api", "billing-api", "users-api"}, apiNames(cfg))
+}
+
+func TestLoadConfig_IncludeImportRelativeToIncludedFile(t *testing.T) {
+	dir := writeTree(t, map[string]string{
+		"config.json":               `{"include": ["teams/pets.json"], "apis": []}`,
+		"teams/pets.json":           `{"name": "pets", "openapi": {"path": "specs/petstore.json"}}`,
+		"teams/specs/petstore.json": petstoreSpec,
+	})
+
+	cfg, err := config.LoadConfig(filepath.Join(dir, "config.json"))
+	require.NoError(t, err)
+	require.Len(t, cfg.APIs, 1)
+	assert.NotEmpty(t, cfg.APIs[0].Endpoints)
+}
+
+func TestLoadConfig_IncludeDuplicateName(t *testing.T) {
+	dir := writeTree(t, map[string]string{
+		"config.json": `{
+			"include": ["apis.d/*.json"],
+			"apis": [{"name": "other"}, {"name": "users-api"}]
+		}`,
+		"apis.d/users.json": `{"apis": [{"name": "users-api"}]}`,
+	})
+
+	_, err := config.LoadConfig(filepath.Join(dir, "config.json"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), filepath.Join(dir, "apis.d", "users.json")+": apis[0]: duplicate API name 'users-api'")
+	assert.Contains(t, err.Error(), "already defined in "+filepath.Join(dir, "config.json")+": apis[1]")
+}
+
+func TestLoadConfig_IncludeCycle(t *testing.T) {
+	dir := writeTree(t, map[string]string{
+		"config.json": `{"include": ["a.json"], "apis": []}`,
+		"a.json":      `{"include": ["b.json"], "apis": [{"name": "a"}]}`,
+		"b.json":      `{"include": ["a.json"], "apis": [{"name": "b"}]}`,
+	})
+
+	_, err := config.LoadConfig(filepath.Join(dir, "config.json"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), filepath.Join(dir, "b.json")+": include[0]: include cycle")
+	assert.Contains(t, err.Error(), filepath.Join(dir, "a.json")+" -> "+filepath.Join(dir, "b.json")+" -> "+filepath.Join(dir, "a.json"))
+}
+
+func TestLoadConfig_IncludeErrors(t *testing.T) {
+	dir := writeTree(t, map[string]string{
+		"missing.json":    `{"include": ["apis.d/users.json"], "apis": []}`,
+		"invalid.json":    `{"include": ["apis.d/*.json"], "apis": []}`,
+		"apis.d/bad.json": `{"name": "bad", "baseUrl": "${BAD_API_URL}"}`,
+	})
+	t.Setenv("BAD_API_URL", "")
+	os.Unsetenv("BAD_API_URL")
+
+	_, err := config.LoadConfig(filepath.Join(dir, "missing.json"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), filepath.Join(dir, "missing.json")+": include[0]: included file")
+
+	_, err = config.LoadConfig(filepath.Join(dir, "invalid.json"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), filepath.Join(dir, "apis.d", "bad.json"))
+	assert.Contains(t, err.Error(), "baseUrl: environment variable BAD_API_URL is not set")
+}
+
+func TestSaveConfig_KeepsIncludes(t *testing.T) {
+	dir := writeTree(t, map[string]string{
+		"config.json":       `{"include": ["apis.d/*.json"], "apis": [{"name": "root-api", "baseUrl": "http://localhost:8080"}]}`,
+		"apis.d/users.json": `{"name": "users-api", "baseUrl": "http://localhost:8081"}`,
+	})
+	configPath := filepath.Join(dir, "config.json")
+
+	cfg, err := config.LoadConfig(configPath)
+	require.NoError(t, err)
+	require.NoError(t, config.SaveConfig(cfg, configPath))
+
+	data, err := os.ReadFile(configPath)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "users-api", "included APIs should stay in their own files")
+
+	reloaded, err := config.LoadConfig(configPath)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"root-api", "users-api"}, apiNames(reloaded))
+}
+
+func TestConfig_FingerprintTracksIncludedFiles(t *testing.T) {
+	dir := writeTree(t, map[string]string{
+		"config.json":       `{"include": ["apis.d/*.json"], "apis": []}`,
+		"apis.d/users.json": `{"name": "users-api", "baseUrl": "http://localhost:8081"}`,
+	})
+
+	cfg, err := config.LoadConfig(filepath.Join(dir, "config.json"))
+	require.NoError(t, err)
+	fingerprint := cfg.Fingerprint()
+	assert.Equal(t, fingerprint, cfg.Fingerprint())
+
+	// A new file matching an include pattern changes the fingerprint
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "apis.d", "orders.json"), []byte(`{"name": "orders-api"}`), 0644))
+	assert.NotEqual(t, fingerprint, cfg.Fingerprint())
+	fingerprint = cfg.Fingerprint()
+
+	// So does modifying an included file
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "apis.d", "users.json"), []byte(`{"name": "users-api", "baseUrl": "http://localhost:19000"}`), 0644))
+	assert.NotEqual(t, fingerprint, cfg.Fingerprint())
+}
+
+func TestLoadConfig_IncludeValidationErrorsNameFile(t *testing.T) {
+	dir := writeTree(t, map[string]string{
+		"config.json": `{
+			"include": ["apis.d/*.json"],
+			"apis": [{"name": "root-api", "baseUrl": "http://localhost:8080"}]
+		}`,
+		"apis.d/users.json": `{"apis": [
+			{"name": "users-api", "baseUrl": "http://localhost:8081"},
+			{"name": "orders-api", "endpoints": [{"name": "list", "method": "GET", "path": "/orders/{id}"}]}
+		]}`,
+	})
+
+	prefix := filepath.Join(dir, "apis.d", "users.json") + ": apis[1]: "
+	cfg, err := config.LoadConfig(filepath.Join(dir, "config.json"))
+	require.NoError(t, err)
+	err = cfg.Validate()
+	require.Error(t, err)
+	assert.Equal(t, prefix+"API orders-api: base URL is required", err.Error())
+
+	_, err = config.LoadConfigWithOptions(filepath.Join(dir, "config.json"), config.LoadOptions{Strict: true})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), prefix+"API orders-api: base URL is required")
+	assert.Contains(t, err.Error(), prefix+"API orders-api, endpoint list: path placeholder '{id}' has no matching path parameter")
+}
@@ -0,0 +1,126 @@
+package config_test
+
+import (
+	"bytes"
+	"log"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"mcp-bridge/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const interpolatedConfig = `{
+  "apis": [
+    {
+      "name": "users-api",
+      "baseUrl": "${USERS_API_URL:-http://localhost:8081}",
+      "headers": {
+        "X-API-Key": "${USERS_API_KEY}",
+        "X-Literal": "$${NOT_A_REFERENCE}"
+      },
+      "auth": [
+        {
+          "type": "basic",
+          "basic": {
+            "username": "${USERS_API_USER}",
+            "password": "${file:secrets/password}"
+          }
+        }
+      ],
+      "endpoints": [
+        {
+          "name": "list_users",
+          "method": "GET",
+          "path": "/users",
+          "parameters": [
+            {"name": "region", "type": "string", "in": "query", "default": "${REGION:-eu}"}
+          ]
+        }
+      ]
+    }
+  ]
+}`
+
+func writeInterpolatedConfig(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "secrets"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "secrets", "password"), []byte("file-password\n"), 0600))
+
+	configPath := filepath.Join(dir, "config.json")
+	require.NoError(t, os.WriteFile(configPath, []byte(interpolatedConfig), 0644))
+	return configPath
+}
+
+func TestLoadConfig_Interpolation(t *testing.T) {
+	configPath := writeInterpolatedConfig(t)
+	t.Setenv("USERS_API_KEY", "env-api-key")
+	t.Setenv("USERS_API_USER", "admin")
+
+	cfg, err := config.LoadConfig(configPath)
+	require.NoError(t, err)
+
+	api := cfg.APIs[0]
+	assert.Equal(t, "http://localhost:8081", api.BaseURL)
+	assert.Equal(t, "env-api-key", api.Headers["X-API-Key"])
+	assert.Equal(t, "${NOT_A_REFERENCE}", api.Headers["X-Literal"])
+	assert.Equal(t, "admin", api.Auth[0].Basic.Username)
+	assert.Equal(t, "file-password", api.Auth[0].Basic.Password)
+	assert.Equal(t, "eu", api.Endpoints[0].Parameters[0].Default)
+
+	t.Setenv("USERS_API_URL", "https://users.example.com")
+	cfg, err = config.LoadConfig(configPath)
+	require.NoError(t, err)
+	assert.Equal(t, "https://users.example.com", cfg.APIs[0].BaseURL)
+}
+
+func TestLoadConfig_InterpolationMissingVariable(t *testing.T) {
+	configPath := writeInterpolatedConfig(t)
+	t.Setenv("USERS_API_USER", "admin")
+	t.Setenv("USERS_API_KEY", "")
+	os.Unsetenv("USERS_API_KEY")
+
+	_, err := config.LoadConfig(configPath)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "apis[0].headers.X-API-Key")
+	assert.Contains(t, err.Error(), "environment variable USERS_API_KEY is not set")
+}
+
+func TestLoadConfig_InterpolationMissingFile(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	require.NoError(t, os.WriteFile(configPath, []byte(`{"apis":[{"name":"a","baseUrl":"${file:/nonexistent/secret}"}]}`), 0644))
+
+	_, err := config.LoadConfig(configPath)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "apis[0].baseUrl")
+	assert.Contains(t, err.Error(), "error reading secret file")
+}
+
+func TestConfig_SecretsMasked(t *testing.T) {
+	configPath := writeInterpolatedConfig(t)
+	t.Setenv("USERS_API_KEY", "env-api-key")
+	t.Setenv("USERS_API_USER", "admin")
+
+	cfg, err := config.LoadConfig(configPath)
+	require.NoError(t, err)
+
+	masker := config.NewMasker(cfg.Secrets())
+	masked := masker.Mask("key=env-api-key password=file-password user=admin")
+	assert.Equal(t, "key=**** password=**** user=admin", masked)
+
+	var buf bytes.Buffer
+	logger := log.New(masker.Writer(&buf), "", 0)
+	logger.Printf("calling with %s", "env-api-key")
+	assert.Equal(t, "calling with ****\n", buf.String())
+}
+
+func TestMasker_EncodedAndShortSecrets(t *testing.T) {
+	masker := config.NewMasker([]string{"c2VjcmV0+a2V5/MTIz==", "admin"})
+
+	assert.Equal(t, "key=**** path=/**** raw=**** user=admin",
+		masker.Mask("key=c2VjcmV0%2Ba2V5%2FMTIz%3D%3D path=/c2VjcmV0+a2V5%2FMTIz== raw=c2VjcmV0+a2V5/MTIz== user=admin"))
+}
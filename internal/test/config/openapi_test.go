@@ -0,0 +1,386 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"mcp-bridge/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const petstoreSpec = `
+openapi: 3.0.3
+info:
+  title: Petstore
+  version: 1.0.0
+servers:
+  - url: https://{env}.example.com/v1
+    variables:
+      env:
+        default: api
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      summary: List all pets
+      tags: [pets]
+      parameters:
+        - $ref: '#/components/parameters/Limit'
+    post:
+      operationId: createPet
+      summary: Create a pet
+      tags: [pets]
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/NewPet'
+  /pets/{petId}:
+    parameters:
+      - name: petId
+        in: path
+        schema:
+          type: integer
+    get:
+      operationId: showPetById
+      description: Info for a specific pet
+      tags: [pets]
+    delete:
+      tags: [admin]
+  /store/inventory:
+    get:
+      operationId: getInventory
+      tags: [store]
+components:
+  parameters:
+    Limit:
+      name: limit
+      in: query
+      description: How many items to return
+      schema:
+        type: integer
+        default: 20
+  schemas:
+    NewPet:
+      allOf:
+        - $ref: '#/components/schemas/PetBase'
+        - type: object
+          required: [name]
+          properties:
+            name:
+              type: string
+              description: Pet name
+    PetBase:
+      type: object
+      properties:
+        tag:
+          type: string
+        vaccinated:
+          type: boolean
+`
+
+func writeSpec(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+func findEndpoint(t *testing.T, endpoints []config.CustomEndpoint, name string) config.CustomEndpoint {
+	t.Helper()
+	for _, endpoint := range endpoints {
+		if endpoint.Name == name {
+			return endpoint
+		}
+	}
+	t.Fatalf("endpoint %s not found", name)
+	return config.CustomEndpoint{}
+}
+
+func TestImportOpenAPI_Operations(t *testing.T) {
+	specPath := writeSpec(t, t.TempDir(), "petstore.yaml", petstoreSpec)
+
+	api, err := config.ImportOpenAPI(specPath, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "Petstore", api.Name)
+	assert.Equal(t, "https://api.example.com/v1", api.BaseURL)
+	assert.Len(t, api.Endpoints, 5)
+
+	list := findEndpoint(t, api.Endpoints, "listPets")
+	assert.Equal(t, "GET", list.Method)
+	assert.Equal(t, "List all pets", list.Description)
+	require.Len(t, list.Parameters, 1)
+	assert.Equal(t, "limit", list.Parameters[0].Name)
+	assert.Equal(t, "query", list.Parameters[0].In)
+	assert.Equal(t, "integer", list.Parameters[0].Type)
+	assert.Equal(t, 20, list.Parameters[0].Default)
+
+	show := findEndpoint(t, api.Endpoints, "showPetById")
+	assert.Equal(t, "Info for a specific pet", show.Description)
+	require.Len(t, show.Parameters, 1)
+	assert.Equal(t, "path", show.Parameters[0].In)
+	assert.True(t, show.Parameters[0].Required)
+
+	create := findEndpoint(t, api.Endpoints, "createPet")
+	require.Len(t, create.Parameters, 3)
+	for _, param := range create.Parameters {
+		assert.Equal(t, "body", param.In)
+		switch param.Name {
+		case "name":
+			assert.True(t, param.Required)
+			assert.Equal(t, "Pet name", param.Description)
+		case "vaccinated":
+			assert.Equal(t, "boolean", param.Type)
+			assert.False(t, param.Required)
+		}
+	}
+
+	// Operations without an operationId are named after method and path
+	findEndpoint(t, api.Endpoints, "delete_pets_petId")
+}
+
+func TestImportOpenAPI_BodyFieldNamedLikeParameter(t *testing.T) {
+	specPath := writeSpec(t, t.TempDir(), "pets.yaml", `
+openapi: 3.0.3
+info: {title: Pets, version: 1.0.0}
+paths:
+  /pets/{id}:
+    put:
+      operationId: replacePet
+      parameters:
+        - {name: id, in: path, required: true, schema: {type: string}}
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              properties:
+                id: {type: integer}
+                name: {type: string}
+`)
+
+	api, err := config.ImportOpenAPI(specPath, nil)
+	require.NoError(t, err)
+	replace := findEndpoint(t, api.Endpoints, "replacePet")
+	require.Len(t, replace.Parameters, 3)
+	assert.Equal(t, config.CustomParameter{Name: "id", Type: "string", Required: true, In: "path"}, replace.Parameters[0])
+	assert.Equal(t, "body_id", replace.Parameters[1].Name)
+	assert.Equal(t, "id", replace.Parameters[1].Field)
+	assert.Equal(t, "integer", replace.Parameters[1].Type)
+	assert.Equal(t, "name", replace.Parameters[2].Name)
+	assert.Empty(t, replace.Parameters[2].Field)
+}
+
+func TestImportOpenAPI_CollidingNames(t *testing.T) {
+	specPath := writeSpec(t, t.TempDir(), "users.yaml", `
+openapi: 3.0.3
+info: {title: Users, version: 1.0.0}
+paths:
+  /users/{id}:
+    get:
+      parameters: [{name: id, in: path, required: true, schema: {type: string}}]
+  /users/id:
+    get: {}
+  /teams:
+    get: {operationId: "list teams"}
+    post: {operationId: "list.teams"}
+`)
+
+	api, err := config.ImportOpenAPI(specPath, nil)
+	require.NoError(t, err)
+	var names []string
+	for _, endpoint := range api.Endpoints {
+		names = append(names, endpoint.Name+" "+endpoint.Method+" "+endpoint.Path)
+	}
+	assert.Equal(t, []string{
+		"list_teams GET /teams",
+		"list_teams_2 POST /teams",
+		"get_users_id GET /users/id",
+		"get_users_id_2 GET /users/{id}",
+	}, names)
+
+	// A hand-written endpoint still replaces the imported one of its name
+	configPath := writeSpec(t, filepath.Dir(specPath), "config.json", `{
+		"apis": [{
+			"name": "users",
+			"baseUrl": "http://localhost:8080",
+			"openapi": {"path": "users.yaml"},
+			"endpoints": [{"name": "get_users_id", "description": "Hand-written", "method": "GET", "path": "/users/id"}]
+		}]
+	}`)
+	cfg, err := config.LoadConfig(configPath)
+	require.NoError(t, err)
+	require.Len(t, cfg.APIs[0].Endpoints, 4)
+	assert.Equal(t, "Hand-written", findEndpoint(t, cfg.APIs[0].Endpoints, "get_users_id").Description)
+	assert.Equal(t, "/users/{id}", findEndpoint(t, cfg.APIs[0].Endpoints, "get_users_id_2").Path)
+}
+
+func TestImportOpenAPI_Filters(t *testing.T) {
+	specPath := writeSpec(t, t.TempDir(), "petstore.yaml", petstoreSpec)
+
+	api, err := config.ImportOpenAPI(specPath, &config.ImportSource{
+		Include: &config.ImportFilter{Tags: []string{"pets"}, Paths: []string{"/store/**"}},
+		Exclude: &config.ImportFilter{OperationIDs: []string{"createPet"}},
+	})
+	require.NoError(t, err)
+
+	names := []string{}
+	for _, endpoint := range api.Endpoints {
+		names = append(names, endpoint.Name)
+	}
+	assert.ElementsMatch(t, []string{"listPets", "showPetById", "getInventory"}, names)
+}
+
+func TestImportOpenAPI_NotOpenAPI3(t *testing.T) {
+	specPath := writeSpec(t, t.TempDir(), "swagger.json", `{"swagger": "2.0", "paths": {}}`)
+
+	_, err := config.ImportOpenAPI(specPath, nil)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "not an OpenAPI 3.x document")
+}
+
+func TestImportOpenAPI_UnresolvableRef(t *testing.T) {
+	specPath := writeSpec(t, t.TempDir(), "broken.json", `{
+		"openapi": "3.0.0",
+		"paths": {"/a": {"get": {"parameters": [{"$ref": "#/components/parameters/Missing"}]}}}
+	}`)
+
+	_, err := config.ImportOpenAPI(specPath, nil)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "unresolvable $ref")
+}
+
+func TestLoadConfig_OpenAPISource(t *testing.T) {
+	tempDir := t.TempDir()
+	writeSpec(t, tempDir, "petstore.yaml", petstoreSpec)
+	configPath := writeSpec(t, tempDir, "config.json", `{
+		"apis": [{
+			"name": "pets",
+			"openapi": {"path": "petstore.yaml", "include": {"operationIds": ["listPets", "showPetById"]}},
+			"endpoints": [{"name": "listPets", "description": "Hand-written", "method": "GET", "path": "/pets"}]
+		}]
+	}`)
+
+	cfg, err := config.LoadConfig(configPath)
+	require.NoError(t, err)
+	require.NoError(t, cfg.Validate())
+
+	api := cfg.APIs[0]
+	assert.Equal(t, "https://api.example.com/v1", api.BaseURL)
+	require.Len(t, api.Endpoints, 2)
+	assert.Equal(t, "Hand-written", api.Endpoints[0].Description)
+	assert.Equal(t, "showPetById", api.Endpoints[1].Name)
+}
+
+func TestSaveConfig_OpenAPISourceRoundTrip(t *testing.T) {
+	tempDir := t.TempDir()
+	writeSpec(t, tempDir, "petstore.yaml", petstoreSpec)
+	configPath := writeSpec(t, tempDir, "config.json", `{
+		"apis": [{
+			"name": "pets",
+			"openapi": {"path": "petstore.yaml"},
+			"endpoints": [{"name": "listPets", "description": "Hand-written", "method": "GET", "path": "/pets"}]
+		}]
+	}`)
+
+	cfg, err := config.LoadConfig(configPath)
+	require.NoError(t, err)
+	require.NoError(t, config.SaveConfig(cfg, configPath))
+
+	data, err := os.ReadFile(configPath)
+	require.NoError(t, err)
+	saved := string(data)
+	assert.Contains(t, saved, "Hand-written")
+	assert.NotContains(t, saved, "showPetById", "imported endpoints should stay in the spec")
+	assert.NotContains(t, saved, "baseUrl", "the base URL comes from the spec")
+	assert.NotContains(t, saved, "timeout")
+	assert.NotContains(t, saved, "server")
+	assert.NotContains(t, saved, "transport")
+
+	// Changes to the spec still show up after the round trip
+	writeSpec(t, tempDir, "petstore.yaml", strings.ReplaceAll(petstoreSpec, "showPetById", "getPet"))
+	reloaded, err := config.LoadConfig(configPath)
+	require.NoError(t, err)
+	names := make([]string, 0, len(reloaded.APIs[0].Endpoints))
+	for _, endpoint := range reloaded.APIs[0].Endpoints {
+		names = append(names, endpoint.Name)
+	}
+	assert.Equal(t, "Hand-written", reloaded.APIs[0].Endpoints[0].Description)
+	assert.Contains(t, names, "getPet")
+	assert.NotContains(t, names, "showPetById")
+	assert.Equal(t, "https://api.example.com/v1", reloaded.APIs[0].BaseURL)
+}
+
+func TestImportOpenAPI_OutputSchema(t *testing.T) {
+	specPath := writeSpec(t, t.TempDir(), "spec.yaml", `
+openapi: 3.0.3
+info:
+  title: Tree
+  version: 1.0.0
+paths:
+  /nodes:
+    get:
+      operationId: listNodes
+      responses:
+        '200':
+          description: All nodes
+          content:
+            application/json:
+              schema:
+                type: array
+                items:
+                  $ref: '#/components/schemas/Node'
+    delete:
+      operationId: deleteNodes
+      responses:
+        '204':
+          description: Deleted
+  /export:
+    get:
+      operationId: exportNodes
+      responses:
+        '200':
+          description: CSV export
+          content:
+            text/csv:
+              schema:
+                type: string
+components:
+  schemas:
+    Node:
+      type: object
+      properties:
+        name:
+          type: string
+        children:
+          type: array
+          items:
+            $ref: '#/components/schemas/Node'
+`)
+
+	api, err := config.ImportOpenAPI(specPath, nil)
+	require.NoError(t, err)
+
+	list := findEndpoint(t, api.Endpoints, "listNodes")
+	assert.Equal(t, map[string]interface{}{
+		"type": "array",
+		"items": map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"name": map[string]interface{}{"type": "string"},
+				"children": map[string]interface{}{
+					"type":  "array",
+					"items": map[string]interface{}{},
+				},
+			},
+		},
+	}, list.OutputSchema, "references are inlined and recursion is cut off")
+
+	assert.Nil(t, findEndpoint(t, api.Endpoints, "deleteNodes").OutputSchema)
+	assert.Nil(t, findEndpoint(t, api.Endpoints, "exportNodes").OutputSchema)
+}
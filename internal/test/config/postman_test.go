@@ -0,0 +1,122 @@
+package config_test
+
+import (
+	"testing"
+
+	"mcp-bridge/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const crmCollection = `{
+  "info": {
+    "name": "CRM",
+    "schema": "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"
+  },
+  "variable": [
+    {"key": "baseUrl", "value": "https://crm.example.com/v2"},
+    {"key": "pageSize", "value": "25"}
+  ],
+  "item": [
+    {
+      "name": "Contacts",
+      "item": [
+        {
+          "name": "Get contact",
+          "request": {
+            "method": "GET",
+            "header": [
+              {"key": "Accept", "value": "application/json"},
+              {"key": "X-Tenant", "value": "{{tenantId}}"}
+            ],
+            "url": {
+              "raw": "{{baseUrl}}/contacts/:contactId?limit={{pageSize}}",
+              "host": ["{{baseUrl}}"],
+              "path": ["contacts", ":contactId"],
+              "query": [
+                {"key": "limit", "value": "{{pageSize}}"},
+                {"key": "debug", "value": "1", "disabled": true}
+              ],
+              "variable": [{"key": "contactId", "description": "Contact identifier"}]
+            }
+          }
+        },
+        {
+          "name": "Create contact",
+          "request": {
+            "method": "POST",
+            "description": "Creates a contact",
+            "url": "{{baseUrl}}/contacts",
+            "body": {
+              "mode": "raw",
+              "raw": "{\"name\": \"{{contactName}}\", \"age\": {{age}}, \"active\": true}"
+            }
+          }
+        }
+      ]
+    },
+    {
+      "name": "Ping",
+      "request": {"method": "GET", "url": "{{baseUrl}}/ping"}
+    }
+  ]
+}`
+
+func TestImportPostman_Collection(t *testing.T) {
+	path := writeSpec(t, t.TempDir(), "crm.postman_collection.json", crmCollection)
+
+	api, err := config.ImportPostman(path, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "CRM", api.Name)
+	assert.Equal(t, "https://crm.example.com/v2", api.BaseURL)
+	require.Len(t, api.Endpoints, 3)
+
+	get := findEndpoint(t, api.Endpoints, "Contacts_Get_contact")
+	assert.Equal(t, "GET", get.Method)
+	assert.Equal(t, "/contacts/{contactId}", get.Path)
+	assert.Equal(t, "application/json", get.Headers["Accept"])
+	require.Len(t, get.Parameters, 3)
+	assert.Equal(t, config.CustomParameter{Name: "contactId", Type: "string", Required: true, Description: "Contact identifier", In: "path"}, get.Parameters[0])
+	assert.Equal(t, config.CustomParameter{Name: "limit", Type: "string", Default: "25", In: "query"}, get.Parameters[1])
+	assert.Equal(t, config.CustomParameter{Name: "X-Tenant", Type: "string", Required: true, In: "header"}, get.Parameters[2])
+
+	create := findEndpoint(t, api.Endpoints, "Contacts_Create_contact")
+	assert.Equal(t, "Creates a contact", create.Description)
+	assert.Equal(t, "/contacts", create.Path)
+	require.Len(t, create.Parameters, 3)
+	for _, param := range create.Parameters {
+		assert.Equal(t, "body", param.In)
+	}
+	assert.Equal(t, true, create.Parameters[0].Default)
+	assert.Equal(t, "age", create.Parameters[1].Name)
+	assert.True(t, create.Parameters[1].Required)
+	assert.Equal(t, "name", create.Parameters[2].Name)
+	assert.True(t, create.Parameters[2].Required)
+
+	findEndpoint(t, api.Endpoints, "Ping")
+}
+
+func TestImportPostman_FolderFilter(t *testing.T) {
+	path := writeSpec(t, t.TempDir(), "crm.json", crmCollection)
+
+	api, err := config.ImportPostman(path, &config.ImportSource{
+		Include: &config.ImportFilter{Tags: []string{"Contacts"}},
+	})
+	require.NoError(t, err)
+	assert.Len(t, api.Endpoints, 2)
+}
+
+func TestLoadConfig_PostmanSource(t *testing.T) {
+	tempDir := t.TempDir()
+	writeSpec(t, tempDir, "crm.json", crmCollection)
+	configPath := writeSpec(t, tempDir, "config.json", `{
+		"apis": [{"name": "crm", "postman": {"path": "crm.json"}}]
+	}`)
+
+	cfg, err := config.LoadConfig(configPath)
+	require.NoError(t, err)
+	require.NoError(t, cfg.Validate())
+	assert.Equal(t, "https://crm.example.com/v2", cfg.APIs[0].BaseURL)
+	assert.Len(t, cfg.APIs[0].Endpoints, 3)
+}
@@ -0,0 +1,179 @@
+package config_test
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	"mcp-bridge/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type schemaNode struct {
+	Ref                  string                 `json:"$ref"`
+	Properties           map[string]*schemaNode `json:"properties"`
+	Items                *schemaNode            `json:"items"`
+	AdditionalProperties interface{}            `json:"additionalProperties"`
+	Definitions          map[string]*schemaNode `json:"definitions"`
+}
+
+// assertSchemaCovers checks that every JSON field of t is described by node
+func assertSchemaCovers(t *testing.T, root, node *schemaNode, typ reflect.Type, path string) {
+	t.Helper()
+	for typ.Kind() == reflect.Ptr || typ.Kind() == reflect.Slice {
+		typ = typ.Elem()
+		if node.Items != nil {
+			node = node.Items
+		}
+	}
+	if node.Ref != "" {
+		node = root.Definitions[strings.TrimPrefix(node.Ref, "#/definitions/")]
+		require.NotNil(t, node, "%s: unresolved $ref", path)
+	}
+	if typ.Kind() != reflect.Struct {
+		return
+	}
+
+	assert.Equal(t, false, node.AdditionalProperties, "%s should not allow additional properties", path)
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		property, ok := node.Properties[name]
+		if assert.True(t, ok, "%s.%s is missing from the schema", path, name) {
+			assertSchemaCovers(t, root, property, field.Type, path+"."+name)
+		}
+	}
+}
+
+func TestJSONSchema_CoversConfig(t *testing.T) {
+	var root schemaNode
+	require.NoError(t, json.Unmarshal(config.JSONSchema(), &root))
+
+	assertSchemaCovers(t, &root, &root, reflect.TypeOf(config.Config{}), "config")
+}
+
+// TestJSONSchema_AcceptsLoaderValues checks that the schema does not flag
+// values the loader accepts
+func TestJSONSchema_AcceptsLoaderValues(t *testing.T) {
+	var root struct {
+		Definitions map[string]struct {
+			Items      struct{ Pattern string } `json:"items"`
+			Properties map[string]struct {
+				Enum []string `json:"enum"`
+			} `json:"properties"`
+		} `json:"definitions"`
+	}
+	require.NoError(t, json.Unmarshal(config.JSONSchema(), &root))
+
+	methods := regexp.MustCompile(root.Definitions["methods"].Items.Pattern)
+	for _, method := range []string{"GET", "get", "Post", "options"} {
+		assert.True(t, methods.MatchString(method), method)
+	}
+	assert.False(t, methods.MatchString("FETCH"))
+
+	cfg := &config.Config{APIs: []config.APIConfig{{
+		Name:           "test-api",
+		BaseURL:        "http://localhost:8080",
+		AllowedMethods: []string{"get", "Post"},
+	}}}
+	require.NoError(t, cfg.Validate())
+
+	types := root.Definitions["parameter"].Properties["type"].Enum
+	for _, typ := range []string{"string", "integer", "int", "number", "float", "double", "boolean", "bool", "array", "object"} {
+		assert.Contains(t, types, typ)
+	}
+}
+
+func TestLoadConfig_SchemaKey(t *testing.T) {
+	configPath := writeConfigFile(t, "config.json", `{
+  "$schema": "./config.schema.json",
+  "apis": [{"name": "users-api", "baseUrl": "http://localhost:8081"}]
+}`)
+
+	cfg, err := config.LoadConfigWithOptions(configPath, config.LoadOptions{Strict: true})
+	require.NoError(t, err)
+	assert.Equal(t, "./config.schema.json", cfg.Schema)
+}
+
+func TestLoadConfig_StrictReportsAllProblems(t *testing.T) {
+	configPath := writeConfigFile(t, "config.json", `{
+  "apis": [{
+    "name": "users-api",
+    "base_url": "http://localhost:8081",
+    "endpoints": [{
+      "name": "get_user",
+      "method": "GET",
+      "path": "/users/{userId}",
+      "parameters": [
+        {"name": "id", "type": "integer", "in": "path", "requierd": true},
+        {"name": "fields", "in": "form"}
+      ]
+    }]
+  }],
+  "sever": {"name": "test"}
+}`)
+
+	cfg, err := config.LoadConfig(configPath)
+	require.NoError(t, err, "unknown fields are ignored without strict mode")
+	require.Error(t, cfg.Validate(), "base URL is missing")
+
+	_, err = config.LoadConfigWithOptions(configPath, config.LoadOptions{Strict: true})
+	require.Error(t, err)
+	msg := err.Error()
+	assert.Contains(t, msg, "apis[0].base_url: unknown field, did you mean 'baseUrl'?")
+	assert.Contains(t, msg, "apis[0].endpoints[0].parameters[0].requierd: unknown field")
+	assert.Contains(t, msg, "sever: unknown field")
+	assert.Contains(t, msg, "API users-api: base URL is required")
+	assert.Contains(t, msg, "parameter fields: location must be 'path', 'query', 'header' or 'body', got 'form'")
+	assert.Contains(t, msg, "path parameter 'id' does not appear in path '/users/{userId}'")
+	assert.Contains(t, msg, "path placeholder '{userId}' has no matching path parameter")
+}
+
+func TestLoadConfig_StrictIncludedFiles(t *testing.T) {
+	dir := writeTree(t, map[string]string{
+		"config.json": `{"include": ["apis/*.json"], "apis": []}`,
+		"apis/orders.json": `{
+  "$schema": "../config.schema.json",
+  "name": "orders-api",
+  "baseUrl": "http://localhost:8082",
+  "timeout_seconds": 10
+}`,
+	})
+
+	_, err := config.LoadConfigWithOptions(filepath.Join(dir, "config.json"), config.LoadOptions{Strict: true})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "orders.json: timeout_seconds: unknown field")
+	assert.NotContains(t, err.Error(), "$schema")
+}
+
+func TestConfig_ValidateStrict(t *testing.T) {
+	cfg := &config.Config{
+		APIs: []config.APIConfig{{
+			Name:    "users-api",
+			BaseURL: "http://localhost:8081",
+			Endpoints: []config.CustomEndpoint{{
+				Name:   "get_user",
+				Method: "GET",
+				Path:   "/users/{id}",
+				Parameters: []config.CustomParameter{
+					{Name: "id", In: "path"},
+					{Name: "limit"},
+				},
+			}},
+		}},
+	}
+
+	require.NoError(t, cfg.ValidateStrict())
+	assert.Equal(t, "query", cfg.APIs[0].Endpoints[0].Parameters[1].In, "defaults still apply")
+}
@@ -0,0 +1,146 @@
+package config_test
+
+import (
+	"testing"
+
+	"mcp-bridge/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const legacySwaggerSpec = `{
+  "swagger": "2.0",
+  "info": {"title": "Legacy Orders", "version": "1.0"},
+  "host": "orders.internal",
+  "basePath": "/api/",
+  "schemes": ["http"],
+  "paths": {
+    "/orders/{orderId}": {
+      "get": {
+        "operationId": "getOrder",
+        "summary": "Fetch an order",
+        "parameters": [
+          {"name": "orderId", "in": "path", "type": "integer", "required": true},
+          {"name": "expand", "in": "query", "type": "boolean"}
+        ],
+        "responses": {
+          "200": {"description": "The order", "schema": {"$ref": "#/definitions/Order"}},
+          "404": {"description": "Not found"}
+        }
+      }
+    },
+    "/orders": {
+      "post": {
+        "operationId": "createOrder",
+        "tags": ["orders"],
+        "parameters": [
+          {"name": "order", "in": "body", "required": true, "schema": {"$ref": "#/definitions/Order"}}
+        ]
+      }
+    },
+    "/uploads": {
+      "post": {
+        "operationId": "upload",
+        "tags": ["files"],
+        "parameters": [
+          {"name": "label", "in": "formData", "type": "string", "required": true}
+        ]
+      }
+    }
+  },
+  "definitions": {
+    "Order": {
+      "type": "object",
+      "required": ["sku"],
+      "properties": {
+        "sku": {"type": "string"},
+        "quantity": {"type": "integer", "default": 1}
+      }
+    }
+  }
+}`
+
+func TestImportSwagger_Operations(t *testing.T) {
+	specPath := writeSpec(t, t.TempDir(), "orders.json", legacySwaggerSpec)
+
+	api, err := config.ImportSwagger(specPath, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "Legacy_Orders", api.Name)
+	assert.Equal(t, "http://orders.internal/api", api.BaseURL)
+	assert.Len(t, api.Endpoints, 3)
+
+	get := findEndpoint(t, api.Endpoints, "getOrder")
+	require.Len(t, get.Parameters, 2)
+	assert.Equal(t, "integer", get.Parameters[0].Type)
+	assert.Equal(t, "path", get.Parameters[0].In)
+	assert.Equal(t, "boolean", get.Parameters[1].Type)
+	require.NotNil(t, get.OutputSchema)
+	assert.Equal(t, "object", get.OutputSchema["type"])
+	assert.Contains(t, get.OutputSchema["properties"], "sku")
+
+	create := findEndpoint(t, api.Endpoints, "createOrder")
+	require.Len(t, create.Parameters, 2)
+	assert.Equal(t, "quantity", create.Parameters[0].Name)
+	assert.Equal(t, "body", create.Parameters[0].In)
+	assert.Equal(t, float64(1), create.Parameters[0].Default)
+	assert.Equal(t, "sku", create.Parameters[1].Name)
+	assert.True(t, create.Parameters[1].Required)
+
+	upload := findEndpoint(t, api.Endpoints, "upload")
+	require.Len(t, upload.Parameters, 1)
+	assert.Equal(t, "body", upload.Parameters[0].In)
+	assert.True(t, upload.Parameters[0].Required)
+}
+
+func TestImportSwagger_BodyFieldNamedLikeParameter(t *testing.T) {
+	specPath := writeSpec(t, t.TempDir(), "orders.json", `{
+  "swagger": "2.0",
+  "info": {"title": "Orders", "version": "1.0"},
+  "paths": {
+    "/orders/{sku}": {
+      "put": {
+        "operationId": "replaceOrder",
+        "parameters": [
+          {"name": "order", "in": "body", "schema": {
+            "type": "object",
+            "properties": {"sku": {"type": "string"}, "quantity": {"type": "integer"}}
+          }},
+          {"name": "sku", "in": "path", "type": "string", "required": true}
+        ]
+      }
+    }
+  }
+}`)
+
+	api, err := config.ImportSwagger(specPath, nil)
+	require.NoError(t, err)
+	replace := findEndpoint(t, api.Endpoints, "replaceOrder")
+	require.Len(t, replace.Parameters, 3)
+	names := make(map[string]config.CustomParameter)
+	for _, param := range replace.Parameters {
+		names[param.Name] = param
+	}
+	assert.Equal(t, "path", names["sku"].In)
+	assert.Equal(t, "body", names["body_sku"].In)
+	assert.Equal(t, "sku", names["body_sku"].Field)
+	assert.Equal(t, "body", names["quantity"].In)
+}
+
+func TestImportSwagger_Filters(t *testing.T) {
+	specPath := writeSpec(t, t.TempDir(), "orders.json", legacySwaggerSpec)
+
+	api, err := config.ImportSwagger(specPath, &config.ImportSource{
+		Exclude: &config.ImportFilter{Tags: []string{"files"}},
+	})
+	require.NoError(t, err)
+	assert.Len(t, api.Endpoints, 2)
+}
+
+func TestImportSwagger_RejectsOpenAPI3(t *testing.T) {
+	specPath := writeSpec(t, t.TempDir(), "spec.yaml", petstoreSpec)
+
+	_, err := config.ImportSwagger(specPath, nil)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "not a Swagger 2.0 document")
+}
@@ -0,0 +1,88 @@
+package jsonpath_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"mcp-bridge/internal/jsonpath"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const document = `{
+  "data": {
+    "items": [
+      {"id": 1, "name": "a", "tags": ["x"]},
+      {"id": 2, "name": "b", "tags": []}
+    ],
+    "total": 2
+  },
+  "odd key": true
+}`
+
+func TestPath_Get(t *testing.T) {
+	var data interface{}
+	require.NoError(t, json.Unmarshal([]byte(document), &data))
+
+	tests := []struct {
+		expr string
+		want interface{}
+	}{
+		{"$.data.total", float64(2)},
+		{"data.total", float64(2)},
+		{"$['odd key']", true},
+		{"$.data.items[0].name", "a"},
+		{"$.data.items[-1].id", float64(2)},
+		{"$.data.items[*].id", []interface{}{float64(1), float64(2)}},
+		{"data.items[*].tags[*]", []interface{}{"x"}},
+		{"$.data.*", []interface{}{data.(map[string]interface{})["data"].(map[string]interface{})["items"], float64(2)}},
+		{"$.data.missing", nil},
+		{"$.data.items[5]", nil},
+		{"$.data.missing[*]", []interface{}{}},
+		{"$", data},
+		{"", data},
+	}
+
+	for _, tt := range tests {
+		path, err := jsonpath.Compile(tt.expr)
+		require.NoError(t, err, tt.expr)
+		assert.Equal(t, tt.want, path.Get(data), tt.expr)
+	}
+}
+
+func TestPath_Schema(t *testing.T) {
+	item := map[string]interface{}{"type": "object", "properties": map[string]interface{}{"id": map[string]interface{}{"type": "integer"}}}
+	items := map[string]interface{}{"type": "array", "items": item}
+	schema := map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"data": map[string]interface{}{"type": "object", "properties": map[string]interface{}{"items": items}},
+		},
+	}
+
+	tests := []struct {
+		expr string
+		want map[string]interface{}
+	}{
+		{"", schema},
+		{"$.data.items", items},
+		{"data.items[0]", item},
+		{"$.data.items[*].id", map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "integer"}}},
+		{"$.data.missing", nil},
+		{"$.data.items.id", nil},
+	}
+
+	for _, tt := range tests {
+		path, err := jsonpath.Compile(tt.expr)
+		require.NoError(t, err, tt.expr)
+		assert.Equal(t, tt.want, path.Schema(schema), tt.expr)
+	}
+}
+
+func TestCompile_Errors(t *testing.T) {
+	for _, expr := range []string{"$.", "$.a..b", "$.items[", "$.items[x]", "$x"} {
+		_, err := jsonpath.Compile(expr)
+		assert.Error(t, err, expr)
+	}
+}
@@ -0,0 +1,314 @@
+package mcp_test
+
+import (
+	"context"
+	"io"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"mcp-bridge/internal/mcp"
+	"mcp-bridge/pkg/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// channelTransport feeds queued messages to the server and collects its output
+type channelTransport struct {
+	in  chan *types.JSONRPCMessage
+	out chan *types.JSONRPCMessage
+}
+
+func newChannelTransport() *channelTransport {
+	return &channelTransport{
+		in:  make(chan *types.JSONRPCMessage, 100),
+		out: make(chan *types.JSONRPCMessage, 100),
+	}
+}
+
+func (t *channelTransport) Start() error { return nil }
+
+func (t *channelTransport) ReadMessage() (*types.JSONRPCMessage, error) {
+	msg, ok := <-t.in
+	if !ok {
+		return nil, io.EOF
+	}
+	return msg, nil
+}
+
+func (t *channelTransport) WriteMessage(msg *types.JSONRPCMessage) error {
+	t.out <- msg
+	return nil
+}
+
+func (t *channelTransport) Close() error { return nil }
+
+func (t *channelTransport) expect(tb testing.TB) *types.JSONRPCMessage {
+	tb.Helper()
+	select {
+	case msg := <-t.out:
+		return msg
+	case <-time.After(2 * time.Second):
+		tb.Fatal("timed out waiting for response")
+		return nil
+	}
+}
+
+func request(id int, method string, params interface{}) *types.JSONRPCMessage {
+	return &types.JSONRPCMessage{JSONRpc: "2.0", ID: id, Method: method, Params: params}
+}
+
+func textResult(text string) *types.CallToolResult {
+	return &types.CallToolResult{Content: []types.ToolResult{{Type: "text", Text: text}}}
+}
+
+func TestServer_SlowToolDoesNotBlockPing(t *testing.T) {
+	tr := newChannelTransport()
+	server := mcp.NewServer(tr)
+
+	release := make(chan struct{})
+	server.SetToolHandler(func(ctx context.Context, name string, args map[string]interface{}) (*types.CallToolResult, error) {
+		<-release
+		return textResult(name), nil
+	})
+
+	done := make(chan error)
+	go func() { done <- server.Start() }()
+
+	tr.in <- request(1, "tools/call", map[string]interface{}{"name": "slow"})
+	tr.in <- request(2, "ping", nil)
+
+	// The ping is answered while the tool call is still blocked
+	assert.Equal(t, 2, tr.expect(t).ID)
+
+	close(release)
+	assert.Equal(t, 1, tr.expect(t).ID)
+
+	close(tr.in)
+	require.NoError(t, <-done)
+}
+
+func TestServer_MaxConcurrency(t *testing.T) {
+	tr := newChannelTransport()
+	server := mcp.NewServer(tr)
+	server.SetMaxConcurrency(2)
+
+	var running, peak int32
+	server.SetToolHandler(func(ctx context.Context, name string, args map[string]interface{}) (*types.CallToolResult, error) {
+		n := atomic.AddInt32(&running, 1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		atomic.AddInt32(&running, -1)
+		return textResult(name), nil
+	})
+
+	done := make(chan error)
+	go func() { done <- server.Start() }()
+
+	const calls = 8
+	for i := 0; i < calls; i++ {
+		tr.in <- request(i, "tools/call", map[string]interface{}{"name": "tool"})
+	}
+
+	seen := make(map[interface{}]bool)
+	for i := 0; i < calls; i++ {
+		seen[tr.expect(t).ID] = true
+	}
+	assert.Len(t, seen, calls)
+	assert.LessOrEqual(t, atomic.LoadInt32(&peak), int32(2))
+
+	close(tr.in)
+	require.NoError(t, <-done)
+}
+
+func TestServer_StartWaitsForInflightRequests(t *testing.T) {
+	tr := newChannelTransport()
+	server := mcp.NewServer(tr)
+	server.SetToolHandler(func(ctx context.Context, name string, args map[string]interface{}) (*types.CallToolResult, error) {
+		time.Sleep(50 * time.Millisecond)
+		return textResult(name), nil
+	})
+
+	tr.in <- request(1, "tools/call", map[string]interface{}{"name": "tool"})
+	close(tr.in)
+
+	require.NoError(t, server.Start())
+	assert.Equal(t, 1, tr.expect(t).ID)
+}
+
+func TestServer_ConcurrentAddToolAndList(t *testing.T) {
+	tr := newChannelTransport()
+	server := mcp.NewServer(tr)
+
+	done := make(chan error)
+	go func() { done <- server.Start() }()
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 50; i++ {
+			server.AddTool(types.Tool{Name: "tool", InputSchema: map[string]interface{}{}})
+		}
+	}()
+
+	for i := 0; i < 50; i++ {
+		tr.in <- request(i, "tools/list", nil)
+		tr.expect(t)
+	}
+	wg.Wait()
+
+	close(tr.in)
+	require.NoError(t, <-done)
+}
+
+func TestServer_NegotiatesProtocolVersion(t *testing.T) {
+	tr := newChannelTransport()
+	server := mcp.NewServer(tr)
+
+	tr.in <- request(1, "initialize", map[string]interface{}{"protocolVersion": "2024-11-05"})
+	tr.in <- request(2, "initialize", map[string]interface{}{"protocolVersion": "1999-01-01"})
+	tr.in <- request(3, "initialize", map[string]interface{}{"protocolVersion": "2025-03-26"})
+	close(tr.in)
+	require.NoError(t, server.Start())
+
+	first := tr.expect(t).Result.(types.InitializeResult)
+	assert.Equal(t, "2024-11-05", first.ProtocolVersion)
+	second := tr.expect(t).Result.(types.InitializeResult)
+	assert.Equal(t, types.LatestProtocolVersion, second.ProtocolVersion)
+	third := tr.expect(t).Result.(types.InitializeResult)
+	assert.Equal(t, "2025-03-26", third.ProtocolVersion)
+}
+
+func TestServer_CancelledRequest(t *testing.T) {
+	tr := newChannelTransport()
+	server := mcp.NewServer(tr)
+
+	started := make(chan struct{})
+	aborted := make(chan error, 1)
+	server.SetToolHandler(func(ctx context.Context, name string, args map[string]interface{}) (*types.CallToolResult, error) {
+		close(started)
+		<-ctx.Done()
+		aborted <- ctx.Err()
+		return textResult(name), nil
+	})
+
+	done := make(chan error)
+	go func() { done <- server.Start() }()
+
+	tr.in <- request(1, "tools/call", map[string]interface{}{"name": "slow"})
+	<-started
+	tr.in <- &types.JSONRPCMessage{
+		JSONRpc: "2.0",
+		Method:  "notifications/cancelled",
+		Params:  map[string]interface{}{"requestId": 1, "reason": "user aborted"},
+	}
+
+	assert.Equal(t, context.Canceled, <-aborted)
+
+	// No response is sent for a cancelled request
+	tr.in <- request(2, "ping", nil)
+	assert.Equal(t, 2, tr.expect(t).ID)
+
+	close(tr.in)
+	require.NoError(t, <-done)
+	assert.Empty(t, tr.out)
+}
+
+func TestServer_CancelQueuedRequest(t *testing.T) {
+	tr := newChannelTransport()
+	server := mcp.NewServer(tr)
+	server.SetMaxConcurrency(1)
+
+	release := make(chan struct{})
+	var calls []string
+	server.SetToolHandler(func(ctx context.Context, name string, args map[string]interface{}) (*types.CallToolResult, error) {
+		calls = append(calls, name)
+		<-release
+		return textResult(name), nil
+	})
+
+	done := make(chan error)
+	go func() { done <- server.Start() }()
+
+	// The second call waits for the only worker, but the server keeps
+	// reading messages
+	tr.in <- request(1, "tools/call", map[string]interface{}{"name": "first"})
+	tr.in <- request(2, "tools/call", map[string]interface{}{"name": "second"})
+	tr.in <- &types.JSONRPCMessage{
+		JSONRpc: "2.0",
+		Method:  "notifications/cancelled",
+		Params:  map[string]interface{}{"requestId": 2},
+	}
+	tr.in <- request(3, "ping", nil)
+	assert.Equal(t, 3, tr.expect(t).ID)
+
+	close(release)
+	assert.Equal(t, 1, tr.expect(t).ID)
+
+	close(tr.in)
+	require.NoError(t, <-done)
+	assert.Empty(t, tr.out)
+	assert.Equal(t, []string{"first"}, calls)
+}
+
+func TestServer_RequestTimeout(t *testing.T) {
+	tr := newChannelTransport()
+	server := mcp.NewServer(tr)
+	server.SetRequestTimeout(20 * time.Millisecond)
+
+	server.SetToolHandler(func(ctx context.Context, name string, args map[string]interface{}) (*types.CallToolResult, error) {
+		<-ctx.Done()
+		return &types.CallToolResult{
+			Content: []types.ToolResult{{Type: "text", Text: ctx.Err().Error()}},
+			IsError: true,
+		}, nil
+	})
+
+	tr.in <- request(1, "tools/call", map[string]interface{}{"name": "slow"})
+	close(tr.in)
+	require.NoError(t, server.Start())
+
+	result := tr.expect(t).Result.(*types.CallToolResult)
+	assert.True(t, result.IsError)
+	assert.Equal(t, context.DeadlineExceeded.Error(), result.Content[0].Text)
+}
+
+func TestServer_SetToolsNotifiesInitializedClients(t *testing.T) {
+	tr := newChannelTransport()
+	server := mcp.NewServer(tr)
+	server.AddTool(types.Tool{Name: "old", InputSchema: map[string]interface{}{}})
+
+	// No notification before the client has initialized
+	server.SetTools([]types.Tool{{Name: "early", InputSchema: map[string]interface{}{}}})
+	require.NoError(t, server.NotifyToolsListChanged())
+	assert.Empty(t, tr.out)
+
+	done := make(chan error)
+	go func() { done <- server.Start() }()
+
+	tr.in <- &types.JSONRPCMessage{JSONRpc: "2.0", Method: "notifications/initialized"}
+	tr.in <- request(1, "ping", nil)
+	tr.expect(t)
+
+	server.SetTools([]types.Tool{{Name: "new", InputSchema: map[string]interface{}{}}})
+	require.NoError(t, server.NotifyToolsListChanged())
+	notification := tr.expect(t)
+	assert.Equal(t, "notifications/tools/list_changed", notification.Method)
+	assert.Nil(t, notification.ID)
+
+	tr.in <- request(2, "tools/list", nil)
+	tools := tr.expect(t).Result.(types.ToolsListResult).Tools
+	require.Len(t, tools, 1)
+	assert.Equal(t, "new", tools[0].Name)
+
+	close(tr.in)
+	require.NoError(t, <-done)
+}
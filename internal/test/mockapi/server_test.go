@@ -0,0 +1,89 @@
+package mockapi_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"mcp-bridge/internal/mockapi"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testConfig() *mockapi.Config {
+	return &mockapi.Config{
+		Server: mockapi.ServerConfig{Port: "0", Name: "Test Mock API"},
+		Resources: []mockapi.ResourceConfig{{
+			Name:       "users",
+			Path:       "/users",
+			Enabled:    true,
+			Methods:    []string{"GET", "POST"},
+			SupportsID: true,
+			Data:       []map[string]interface{}{{"id": 1, "name": "John Doe"}},
+		}},
+		Endpoints: []mockapi.EndpointConfig{{
+			Path:     "/health",
+			Method:   "GET",
+			Enabled:  true,
+			Response: map[string]interface{}{"status": "ok"},
+		}},
+	}
+}
+
+func get(t *testing.T, req *http.Request) (int, string) {
+	t.Helper()
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp.StatusCode, string(body)
+}
+
+func TestServer_Resources(t *testing.T) {
+	server := httptest.NewServer(mockapi.NewServer(testConfig()).Handler())
+	defer server.Close()
+
+	req, _ := http.NewRequest("GET", server.URL+"/health", nil)
+	status, body := get(t, req)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Contains(t, body, `"status":"ok"`)
+
+	req, _ = http.NewRequest("GET", server.URL+"/users/1", nil)
+	status, body = get(t, req)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Contains(t, body, "John Doe")
+
+	req, _ = http.NewRequest("DELETE", server.URL+"/users", nil)
+	status, _ = get(t, req)
+	assert.Equal(t, http.StatusMethodNotAllowed, status)
+}
+
+func TestServer_OAuth2(t *testing.T) {
+	cfg := testConfig()
+	cfg.Auth = mockapi.AuthConfig{Enabled: true, Type: "oauth2", Username: "client", Password: "secret"}
+	server := httptest.NewServer(mockapi.NewServer(cfg).Handler())
+	defer server.Close()
+
+	req, _ := http.NewRequest("GET", server.URL+"/users", nil)
+	status, _ := get(t, req)
+	assert.Equal(t, http.StatusUnauthorized, status)
+
+	req, _ = http.NewRequest("POST", server.URL+"/oauth/token", strings.NewReader(url.Values{"grant_type": {"client_credentials"}}.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth("client", "secret")
+	status, body := get(t, req)
+	require.Equal(t, http.StatusOK, status)
+	token := body[strings.Index(body, `"access_token":"`)+len(`"access_token":"`):]
+	token = token[:strings.Index(token, `"`)]
+
+	req, _ = http.NewRequest("GET", server.URL+"/users", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	status, body = get(t, req)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Contains(t, body, "John Doe")
+}
@@ -0,0 +1,484 @@
+package transport_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"mcp-bridge/internal/transport"
+	"mcp-bridge/pkg/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// startEchoServer serves the transport over httptest and answers every
+// request with a result naming the method that was called.
+func startEchoServer(t *testing.T) (*transport.HTTPTransport, *httptest.Server) {
+	t.Helper()
+	return startEchoServerWith(t, &transport.HTTPConfig{CORS: true})
+}
+
+func startEchoServerWith(t *testing.T, config *transport.HTTPConfig) (*transport.HTTPTransport, *httptest.Server) {
+	t.Helper()
+
+	httpTransport := transport.NewHTTPTransport(config)
+	server := httptest.NewServer(httpTransport.Handler())
+
+	go func() {
+		for {
+			msg, err := httpTransport.ReadMessage()
+			if err != nil {
+				return
+			}
+			if msg == nil || msg.ID == nil {
+				continue
+			}
+			httpTransport.WriteMessage(&types.JSONRPCMessage{
+				JSONRpc: "2.0",
+				ID:      msg.ID,
+				Result:  map[string]interface{}{"method": msg.Method},
+			})
+		}
+	}()
+
+	t.Cleanup(func() {
+		server.Close()
+		httpTransport.Close()
+	})
+	return httpTransport, server
+}
+
+func postMCP(t *testing.T, server *httptest.Server, sessionID, accept, body string) *http.Response {
+	t.Helper()
+
+	req, err := http.NewRequest("POST", server.URL+"/mcp", strings.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	if sessionID != "" {
+		req.Header.Set("Mcp-Session-Id", sessionID)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	return resp
+}
+
+func initializeSession(t *testing.T, server *httptest.Server) string {
+	t.Helper()
+
+	resp := postMCP(t, server, "", "application/json", `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-06-18"}}`)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	sessionID := resp.Header.Get("Mcp-Session-Id")
+	require.NotEmpty(t, sessionID)
+	return sessionID
+}
+
+// readSSEEvent reads the next event from an SSE stream, skipping comments
+func readSSEEvent(t *testing.T, reader *bufio.Reader) (string, string) {
+	t.Helper()
+
+	var id, data string
+	for {
+		line, err := reader.ReadString('\n')
+		require.NoError(t, err)
+		line = strings.TrimRight(line, "\n")
+
+		switch {
+		case line == "" && data != "":
+			return id, data
+		case strings.HasPrefix(line, "id: "):
+			id = strings.TrimPrefix(line, "id: ")
+		case strings.HasPrefix(line, "data: "):
+			data = strings.TrimPrefix(line, "data: ")
+		}
+	}
+}
+
+func openStream(t *testing.T, server *httptest.Server, sessionID, lastEventID string) (*http.Response, *bufio.Reader) {
+	t.Helper()
+
+	req, err := http.NewRequest("GET", server.URL+"/mcp", nil)
+	require.NoError(t, err)
+	req.Header.Set("Accept", "text/event-stream")
+	req.Header.Set("Mcp-Session-Id", sessionID)
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+	return resp, bufio.NewReader(resp.Body)
+}
+
+func TestHTTPTransport_SessionLifecycle(t *testing.T) {
+	_, server := startEchoServer(t)
+	sessionID := initializeSession(t, server)
+
+	resp := postMCP(t, server, sessionID, "application/json", `{"jsonrpc":"2.0","id":2,"method":"tools/list"}`)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+
+	var msg types.JSONRPCMessage
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&msg))
+	assert.Equal(t, float64(2), msg.ID)
+	assert.Equal(t, "tools/list", msg.Result.(map[string]interface{})["method"])
+
+	req, err := http.NewRequest("DELETE", server.URL+"/mcp", nil)
+	require.NoError(t, err)
+	req.Header.Set("Mcp-Session-Id", sessionID)
+	deleteResp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	deleteResp.Body.Close()
+	assert.Equal(t, http.StatusNoContent, deleteResp.StatusCode)
+
+	afterDelete := postMCP(t, server, sessionID, "application/json", `{"jsonrpc":"2.0","id":3,"method":"ping"}`)
+	afterDelete.Body.Close()
+	assert.Equal(t, http.StatusNotFound, afterDelete.StatusCode)
+}
+
+func TestHTTPTransport_MaxSessionsEvictsLeastRecentlyUsed(t *testing.T) {
+	_, server := startEchoServerWith(t, &transport.HTTPConfig{MaxSessions: 2})
+	first := initializeSession(t, server)
+	second := initializeSession(t, server)
+
+	// Using the first session makes the second the least recently used
+	time.Sleep(10 * time.Millisecond)
+	used := postMCP(t, server, first, "application/json", `{"jsonrpc":"2.0","id":2,"method":"ping"}`)
+	used.Body.Close()
+	require.Equal(t, http.StatusOK, used.StatusCode)
+
+	third := initializeSession(t, server)
+
+	for sessionID, status := range map[string]int{first: http.StatusOK, second: http.StatusNotFound, third: http.StatusOK} {
+		resp := postMCP(t, server, sessionID, "application/json", `{"jsonrpc":"2.0","id":3,"method":"ping"}`)
+		resp.Body.Close()
+		assert.Equal(t, status, resp.StatusCode)
+	}
+}
+
+func TestHTTPTransport_IdleSessionsExpire(t *testing.T) {
+	_, server := startEchoServerWith(t, &transport.HTTPConfig{SessionIdleTimeout: 50 * time.Millisecond})
+	idle := initializeSession(t, server)
+
+	time.Sleep(100 * time.Millisecond)
+	active := initializeSession(t, server)
+
+	expired := postMCP(t, server, idle, "application/json", `{"jsonrpc":"2.0","id":2,"method":"ping"}`)
+	expired.Body.Close()
+	assert.Equal(t, http.StatusNotFound, expired.StatusCode)
+
+	resp := postMCP(t, server, active, "application/json", `{"jsonrpc":"2.0","id":2,"method":"ping"}`)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestHTTPTransport_IdleSessionsExpireWithoutNewSessions(t *testing.T) {
+	_, server := startEchoServerWith(t, &transport.HTTPConfig{SessionIdleTimeout: 50 * time.Millisecond})
+	idle := initializeSession(t, server)
+
+	time.Sleep(150 * time.Millisecond)
+
+	expired := postMCP(t, server, idle, "application/json", `{"jsonrpc":"2.0","id":2,"method":"ping"}`)
+	expired.Body.Close()
+	assert.Equal(t, http.StatusNotFound, expired.StatusCode)
+}
+
+func TestHTTPTransport_SessionRequired(t *testing.T) {
+	_, server := startEchoServer(t)
+
+	missing := postMCP(t, server, "", "application/json", `{"jsonrpc":"2.0","id":1,"method":"ping"}`)
+	missing.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, missing.StatusCode)
+
+	unknown := postMCP(t, server, "does-not-exist", "application/json", `{"jsonrpc":"2.0","id":1,"method":"ping"}`)
+	unknown.Body.Close()
+	assert.Equal(t, http.StatusNotFound, unknown.StatusCode)
+}
+
+func TestHTTPTransport_NotificationAccepted(t *testing.T) {
+	_, server := startEchoServer(t)
+	sessionID := initializeSession(t, server)
+
+	resp := postMCP(t, server, sessionID, "application/json, text/event-stream", `{"jsonrpc":"2.0","method":"notifications/initialized"}`)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusAccepted, resp.StatusCode)
+}
+
+func TestHTTPTransport_UnsupportedProtocolVersion(t *testing.T) {
+	_, server := startEchoServer(t)
+	sessionID := initializeSession(t, server)
+
+	req, err := http.NewRequest("POST", server.URL+"/mcp", strings.NewReader(`{"jsonrpc":"2.0","id":2,"method":"ping"}`))
+	require.NoError(t, err)
+	req.Header.Set("Mcp-Session-Id", sessionID)
+	req.Header.Set("MCP-Protocol-Version", "1999-01-01")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestHTTPTransport_SupportedProtocolVersions(t *testing.T) {
+	_, server := startEchoServer(t)
+	sessionID := initializeSession(t, server)
+
+	for _, version := range types.SupportedProtocolVersions {
+		req, err := http.NewRequest("POST", server.URL+"/mcp", strings.NewReader(`{"jsonrpc":"2.0","method":"notifications/initialized"}`))
+		require.NoError(t, err)
+		req.Header.Set("Mcp-Session-Id", sessionID)
+		req.Header.Set("MCP-Protocol-Version", version)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusAccepted, resp.StatusCode, version)
+	}
+	assert.Contains(t, types.SupportedProtocolVersions, "2025-03-26")
+}
+
+func TestHTTPTransport_PostSSEResponse(t *testing.T) {
+	_, server := startEchoServer(t)
+	sessionID := initializeSession(t, server)
+
+	resp := postMCP(t, server, sessionID, "application/json, text/event-stream", `{"jsonrpc":"2.0","id":7,"method":"ping"}`)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	id, data := readSSEEvent(t, bufio.NewReader(resp.Body))
+	assert.NotEmpty(t, id)
+
+	var msg types.JSONRPCMessage
+	require.NoError(t, json.Unmarshal([]byte(data), &msg))
+	assert.Equal(t, float64(7), msg.ID)
+}
+
+func TestHTTPTransport_GetStreamAndResume(t *testing.T) {
+	httpTransport, server := startEchoServer(t)
+	sessionID := initializeSession(t, server)
+
+	resp, reader := openStream(t, server, sessionID, "")
+
+	notify := func(n int) {
+		require.NoError(t, httpTransport.WriteMessage(&types.JSONRPCMessage{
+			JSONRpc: "2.0",
+			Method:  "notifications/message",
+			Params:  map[string]interface{}{"n": n},
+		}))
+	}
+
+	notify(1)
+	firstID, data := readSSEEvent(t, reader)
+	assert.Contains(t, data, `"n":1`)
+	resp.Body.Close()
+
+	// Events published while disconnected are replayed after Last-Event-ID
+	notify(2)
+	notify(3)
+
+	resumed, resumedReader := openStream(t, server, sessionID, firstID)
+	defer resumed.Body.Close()
+
+	_, data = readSSEEvent(t, resumedReader)
+	assert.Contains(t, data, `"n":2`)
+	_, data = readSSEEvent(t, resumedReader)
+	assert.Contains(t, data, `"n":3`)
+}
+
+func TestHTTPTransport_GetRequiresEventStream(t *testing.T) {
+	_, server := startEchoServer(t)
+	sessionID := initializeSession(t, server)
+
+	req, err := http.NewRequest("GET", server.URL+"/mcp", nil)
+	require.NoError(t, err)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Mcp-Session-Id", sessionID)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotAcceptable, resp.StatusCode)
+}
+
+func TestHTTPTransport_ConcurrentClientsSameID(t *testing.T) {
+	_, server := startEchoServer(t)
+
+	const clients = 10
+	sessions := make([]string, clients)
+	for i := range sessions {
+		sessions[i] = initializeSession(t, server)
+	}
+
+	var wg sync.WaitGroup
+	results := make([]string, clients)
+	for i := 0; i < clients; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			// Every client uses the same JSON-RPC ID but calls a different method
+			body := fmt.Sprintf(`{"jsonrpc":"2.0","id":1,"method":"method-%d"}`, i)
+			resp := postMCP(t, server, sessions[i], "application/json", body)
+			defer resp.Body.Close()
+
+			var msg types.JSONRPCMessage
+			if err := json.NewDecoder(resp.Body).Decode(&msg); err != nil {
+				return
+			}
+			if result, ok := msg.Result.(map[string]interface{}); ok && msg.ID == float64(1) {
+				results[i], _ = result["method"].(string)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	for i, method := range results {
+		assert.Equal(t, fmt.Sprintf("method-%d", i), method)
+	}
+}
+
+func TestHTTPTransport_NotificationDoesNotWait(t *testing.T) {
+	httpTransport := transport.NewHTTPTransport(&transport.HTTPConfig{})
+	server := httptest.NewServer(httpTransport.Handler())
+	defer server.Close()
+	defer httpTransport.Close()
+
+	// Answer only the initialize request; notifications must not need a reply
+	go func() {
+		msg, err := httpTransport.ReadMessage()
+		if err != nil {
+			return
+		}
+		httpTransport.WriteMessage(&types.JSONRPCMessage{JSONRpc: "2.0", ID: msg.ID, Result: map[string]interface{}{}})
+		for {
+			if _, err := httpTransport.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	sessionID := initializeSession(t, server)
+
+	start := time.Now()
+	resp := postMCP(t, server, sessionID, "application/json", `{"jsonrpc":"2.0","method":"notifications/initialized"}`)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusAccepted, resp.StatusCode)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestHTTPTransport_ClientResponseDropped(t *testing.T) {
+	httpTransport := transport.NewHTTPTransport(&transport.HTTPConfig{})
+	server := httptest.NewServer(httpTransport.Handler())
+	defer server.Close()
+	defer httpTransport.Close()
+
+	received := make(chan *types.JSONRPCMessage, 10)
+	go func() {
+		msg, err := httpTransport.ReadMessage()
+		if err != nil {
+			return
+		}
+		httpTransport.WriteMessage(&types.JSONRPCMessage{JSONRpc: "2.0", ID: msg.ID, Result: map[string]interface{}{}})
+		for {
+			msg, err := httpTransport.ReadMessage()
+			if err != nil {
+				return
+			}
+			received <- msg
+		}
+	}()
+
+	sessionID := initializeSession(t, server)
+
+	resp := postMCP(t, server, sessionID, "application/json", `{"jsonrpc":"2.0","id":"http-1","result":{}}`)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusAccepted, resp.StatusCode)
+
+	resp = postMCP(t, server, sessionID, "application/json", `{"jsonrpc":"2.0","method":"notifications/initialized"}`)
+	resp.Body.Close()
+	require.Equal(t, http.StatusAccepted, resp.StatusCode)
+
+	select {
+	case msg := <-received:
+		assert.Equal(t, "notifications/initialized", msg.Method, "the response is not delivered to the server")
+	case <-time.After(time.Second):
+		t.Fatal("the notification was not delivered")
+	}
+}
+
+func TestHTTPTransport_ReadMessageAfterClose(t *testing.T) {
+	httpTransport := transport.NewHTTPTransport(&transport.HTTPConfig{})
+	require.NoError(t, httpTransport.Close())
+
+	msg, err := httpTransport.ReadMessage()
+	assert.Nil(t, msg)
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestHTTPTransport_WriteUnknownResponse(t *testing.T) {
+	httpTransport := transport.NewHTTPTransport(&transport.HTTPConfig{})
+	defer httpTransport.Close()
+
+	err := httpTransport.WriteMessage(&types.JSONRPCMessage{JSONRpc: "2.0", ID: 42, Result: map[string]interface{}{}})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "no pending request")
+}
+
+func TestHTTPTransport_CancellationReleasesRequest(t *testing.T) {
+	httpTransport := transport.NewHTTPTransport(&transport.HTTPConfig{})
+	server := httptest.NewServer(httpTransport.Handler())
+	defer server.Close()
+	defer httpTransport.Close()
+
+	forwardedID := make(chan interface{}, 1)
+	cancelledID := make(chan interface{}, 1)
+	go func() {
+		for {
+			msg, err := httpTransport.ReadMessage()
+			if err != nil {
+				return
+			}
+			switch msg.Method {
+			case "initialize":
+				httpTransport.WriteMessage(&types.JSONRPCMessage{JSONRpc: "2.0", ID: msg.ID, Result: map[string]interface{}{}})
+			case "tools/call":
+				// Never answered; the client cancels it instead
+				forwardedID <- msg.ID
+			case "notifications/cancelled":
+				cancelledID <- msg.Params.(map[string]interface{})["requestId"]
+			}
+		}
+	}()
+
+	sessionID := initializeSession(t, server)
+
+	callDone := make(chan int, 1)
+	go func() {
+		resp := postMCP(t, server, sessionID, "application/json", `{"jsonrpc":"2.0","id":5,"method":"tools/call","params":{"name":"slow"}}`)
+		resp.Body.Close()
+		callDone <- resp.StatusCode
+	}()
+
+	id := <-forwardedID
+	resp := postMCP(t, server, sessionID, "application/json", `{"jsonrpc":"2.0","method":"notifications/cancelled","params":{"requestId":5}}`)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusAccepted, resp.StatusCode)
+
+	// The server sees the ID the request was forwarded under
+	assert.Equal(t, id, <-cancelledID)
+	assert.Equal(t, http.StatusNoContent, <-callDone)
+}
@@ -1,6 +1,7 @@
 package transport
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -16,13 +17,36 @@ import (
 type HTTPTransport struct {
 	config     *HTTPConfig
 	server     *http.Server
-	messageCh  chan *types.JSONRPCMessage
-	responseCh chan *types.JSONRPCMessage
-	closed     bool
-	mu         sync.RWMutex
-	wg         sync.WaitGroup
+	messageCh chan *types.JSONRPCMessage
+	sessions  map[string]*httpSession
+	pending   map[string]*pendingRequest
+	nextID    uint64
+	done      chan struct{}
+	closed    bool
+	mu        sync.RWMutex
+	wg        sync.WaitGroup
 }
 
+// pendingRequest tracks a client request forwarded to the server until its
+// response is written. Requests are forwarded under a transport-unique ID so
+// that clients reusing the same JSON-RPC ID cannot receive each other's
+// responses.
+type pendingRequest struct {
+	originalID interface{}
+	session    *httpSession
+	stream     int // SSE stream the response belongs to, 0 for a JSON reply
+	reply      chan sseEvent
+}
+
+const (
+	sessionIDHeader       = "Mcp-Session-Id"
+	protocolVersionHeader = "MCP-Protocol-Version"
+	lastEventIDHeader     = "Last-Event-ID"
+
+	// keepAliveInterval is how often an idle GET stream receives an SSE comment
+	keepAliveInterval = 30 * time.Second
+)
+
 // NewHTTPTransport creates a new HTTP transport
 func NewHTTPTransport(config *HTTPConfig) *HTTPTransport {
 	if config.Host == "" {
@@ -31,26 +55,34 @@ func NewHTTPTransport(config *HTTPConfig) *HTTPTransport {
 	if config.Port == 0 {
 		config.Port = 8080
 	}
+	if config.SessionIdleTimeout == 0 {
+		config.SessionIdleTimeout = DefaultSessionIdleTimeout
+	}
+	if config.MaxSessions == 0 {
+		config.MaxSessions = DefaultMaxSessions
+	}
+
+	t := &HTTPTransport{
+		config:    config,
+		messageCh: make(chan *types.JSONRPCMessage, 100),
+		sessions:  make(map[string]*httpSession),
+		pending:   make(map[string]*pendingRequest),
+		done:      make(chan struct{}),
+		closed:    false,
+	}
 
-	return &HTTPTransport{
-		config:     config,
-		messageCh:  make(chan *types.JSONRPCMessage, 100),
-		responseCh: make(chan *types.JSONRPCMessage, 100),
-		closed:     false,
+	if config.SessionIdleTimeout > 0 {
+		t.wg.Add(1)
+		go t.expireSessions()
 	}
+	return t
 }
 
 // Start begins listening for HTTP requests
 func (t *HTTPTransport) Start() error {
-	mux := http.NewServeMux()
-	mux.HandleFunc("/mcp", t.handleMCPRequest)
-	if t.config.CORS {
-		mux.HandleFunc("/", t.handleCORS)
-	}
-
 	t.server = &http.Server{
 		Addr:    fmt.Sprintf("%s:%d", t.config.Host, t.config.Port),
-		Handler: mux,
+		Handler: t.Handler(),
 	}
 
 	t.wg.Add(1)
@@ -64,25 +96,31 @@ func (t *HTTPTransport) Start() error {
 	return nil
 }
 
-// ReadMessage reads a single JSON-RPC message from the HTTP transport
-func (t *HTTPTransport) ReadMessage() (*types.JSONRPCMessage, error) {
-	t.mu.RLock()
-	closed := t.closed
-	t.mu.RUnlock()
-
-	if closed {
-		return nil, fmt.Errorf("transport is closed")
+// Handler returns the HTTP handler serving the MCP endpoint, for use with
+// an existing HTTP server
+func (t *HTTPTransport) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/mcp", t.handleMCPRequest)
+	if t.config.CORS {
+		mux.HandleFunc("/", t.handleCORS)
 	}
+	return mux
+}
 
+// ReadMessage blocks until a JSON-RPC message arrives over HTTP. It returns
+// io.EOF once the transport is closed.
+func (t *HTTPTransport) ReadMessage() (*types.JSONRPCMessage, error) {
 	select {
 	case msg := <-t.messageCh:
 		return msg, nil
-	case <-time.After(100 * time.Millisecond):
-		return nil, nil // No message available, return nil to continue polling
+	case <-t.done:
+		return nil, io.EOF
 	}
 }
 
-// WriteMessage writes a JSON-RPC message to the HTTP transport
+// WriteMessage writes a JSON-RPC message to the HTTP transport. Responses
+// are routed by ID to the POST request they answer, while server-initiated
+// messages are delivered on each session's GET stream.
 func (t *HTTPTransport) WriteMessage(msg *types.JSONRPCMessage) error {
 	t.mu.RLock()
 	closed := t.closed
@@ -92,12 +130,46 @@ func (t *HTTPTransport) WriteMessage(msg *types.JSONRPCMessage) error {
 		return fmt.Errorf("transport is closed")
 	}
 
-	select {
-	case t.responseCh <- msg:
+	if msg.ID == nil && msg.Method != "" {
+		data, err := json.Marshal(msg)
+		if err != nil {
+			return fmt.Errorf("error marshaling message: %w", err)
+		}
+
+		t.mu.RLock()
+		for _, session := range t.sessions {
+			session.publish(data)
+		}
+		t.mu.RUnlock()
 		return nil
-	case <-time.After(5 * time.Second):
-		return fmt.Errorf("timeout writing response")
 	}
+
+	key := fmt.Sprint(msg.ID)
+	t.mu.Lock()
+	pending, ok := t.pending[key]
+	delete(t.pending, key)
+	t.mu.Unlock()
+
+	if !ok {
+		return fmt.Errorf("no pending request for response ID %v", msg.ID)
+	}
+
+	response := *msg
+	response.ID = pending.originalID
+	data, err := json.Marshal(&response)
+	if err != nil {
+		return fmt.Errorf("error marshaling message: %w", err)
+	}
+
+	// Keep SSE responses in the session history so a client that lost the
+	// stream can still collect them with Last-Event-ID
+	event := sseEvent{data: data}
+	if pending.stream != 0 {
+		event = pending.session.record(pending.stream, data)
+	}
+
+	pending.reply <- event
+	return nil
 }
 
 // Close closes the HTTP transport
@@ -108,11 +180,14 @@ func (t *HTTPTransport) Close() error {
 		return nil
 	}
 	t.closed = true
+	close(t.done)
+	for id, session := range t.sessions {
+		session.close()
+		delete(t.sessions, id)
+	}
+	t.pending = make(map[string]*pendingRequest)
 	t.mu.Unlock()
 
-	close(t.messageCh)
-	close(t.responseCh)
-
 	if t.server != nil {
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
@@ -125,12 +200,10 @@ func (t *HTTPTransport) Close() error {
 	return nil
 }
 
-// handleMCPRequest handles incoming MCP JSON-RPC requests
+// handleMCPRequest dispatches requests to the Streamable HTTP endpoint
 func (t *HTTPTransport) handleMCPRequest(w http.ResponseWriter, r *http.Request) {
 	if t.config.CORS {
-		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+		setCORSHeaders(w)
 	}
 
 	if r.Method == "OPTIONS" {
@@ -138,51 +211,378 @@ func (t *HTTPTransport) handleMCPRequest(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	if r.Method != "POST" {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	if version := r.Header.Get(protocolVersionHeader); version != "" && !types.IsSupportedProtocolVersion(version) {
+		http.Error(w, fmt.Sprintf("Unsupported protocol version: %s", version), http.StatusBadRequest)
 		return
 	}
 
+	switch r.Method {
+	case "POST":
+		t.handlePost(w, r)
+	case "GET":
+		t.handleGet(w, r)
+	case "DELETE":
+		t.handleDelete(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handlePost delivers a client message to the server. Requests are answered
+// with a JSON body, or an SSE stream when the client accepts one; notifications
+// are acknowledged with 202 Accepted. Responses are acknowledged the same way
+// and dropped.
+func (t *HTTPTransport) handlePost(w http.ResponseWriter, r *http.Request) {
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
 		http.Error(w, "Error reading request body", http.StatusBadRequest)
 		return
 	}
 
+	if trimmed := bytes.TrimSpace(body); len(trimmed) > 0 && trimmed[0] == '[' {
+		http.Error(w, "Batch requests are not supported", http.StatusBadRequest)
+		return
+	}
+
 	var msg types.JSONRPCMessage
 	if err := json.Unmarshal(body, &msg); err != nil {
 		http.Error(w, "Invalid JSON-RPC message", http.StatusBadRequest)
 		return
 	}
 
-	// Send the message to the message channel
-	select {
-	case t.messageCh <- &msg:
-	case <-time.After(5 * time.Second):
-		http.Error(w, "Timeout processing request", http.StatusRequestTimeout)
+	var session *httpSession
+	if msg.Method == "initialize" {
+		session, err = t.createSession()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set(sessionIDHeader, session.id)
+	} else {
+		var status int
+		session, status = t.lookupSession(r)
+		if session == nil {
+			http.Error(w, http.StatusText(status), status)
+			return
+		}
+	}
+
+	// The server sends no requests to clients, so a response from the client
+	// answers nothing. Delivering it would make the server treat it as a
+	// request with the client's ID.
+	if msg.Method == "" {
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	// Notifications need no reply
+	if msg.ID == nil {
+		if msg.Method == "notifications/cancelled" {
+			t.translateCancellation(&msg, session)
+		}
+		if !t.deliver(&msg) {
+			http.Error(w, "Transport is closed", http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	pending := &pendingRequest{
+		originalID: msg.ID,
+		session:    session,
+		reply:      make(chan sseEvent, 1),
+	}
+	useSSE := acceptsEventStream(r)
+	if useSSE {
+		pending.stream = session.openStream()
+	}
+
+	key := t.registerPending(pending)
+	forwarded := msg
+	forwarded.ID = key
+
+	if !t.deliver(&forwarded) {
+		t.removePending(key)
+		http.Error(w, "Transport is closed", http.StatusServiceUnavailable)
 		return
 	}
 
-	// Wait for response
+	if useSSE {
+		setSSEHeaders(w)
+		w.WriteHeader(http.StatusOK)
+		if flusher, ok := w.(http.Flusher); ok {
+			flusher.Flush()
+		}
+	}
+
 	select {
-	case response := <-t.responseCh:
+	case event, ok := <-pending.reply:
+		if !ok {
+			// Cancelled by the client; end the request without a response
+			if !useSSE {
+				w.WriteHeader(http.StatusNoContent)
+			}
+			return
+		}
+		if useSSE {
+			writeSSEEvent(w, event)
+			return
+		}
 		w.Header().Set("Content-Type", "application/json")
-		if err := json.NewEncoder(w).Encode(response); err != nil {
-			http.Error(w, "Error encoding response", http.StatusInternalServerError)
+		w.Write(event.data)
+	case <-r.Context().Done():
+		// A JSON reply has nowhere to go once the client is gone, but an SSE
+		// response is still recorded in the session history for resumption
+		if !useSSE {
+			t.removePending(key)
+		}
+	case <-t.done:
+		if !useSSE {
+			http.Error(w, "Transport is closed", http.StatusServiceUnavailable)
+		}
+	}
+}
+
+// deliver hands a message to ReadMessage, reporting false if the transport
+// closed first
+func (t *HTTPTransport) deliver(msg *types.JSONRPCMessage) bool {
+	select {
+	case t.messageCh <- msg:
+		return true
+	case <-t.done:
+		return false
+	}
+}
+
+// registerPending stores a request awaiting its response and returns the
+// transport-unique ID it is forwarded under
+func (t *HTTPTransport) registerPending(pending *pendingRequest) string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.nextID++
+	key := fmt.Sprintf("http-%d", t.nextID)
+	t.pending[key] = pending
+	return key
+}
+
+// translateCancellation rewrites the requestId of a cancellation notification
+// to the ID its request was forwarded under, so the server can find it. The
+// server sends no response to a cancelled request, so the waiting POST is
+// released here.
+func (t *HTTPTransport) translateCancellation(msg *types.JSONRPCMessage, session *httpSession) {
+	params, ok := msg.Params.(map[string]interface{})
+	if !ok || params["requestId"] == nil {
+		return
+	}
+	requestID := fmt.Sprint(params["requestId"])
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for key, pending := range t.pending {
+		if pending.session == session && fmt.Sprint(pending.originalID) == requestID {
+			params["requestId"] = key
+			delete(t.pending, key)
+			close(pending.reply)
+			return
 		}
-	case <-time.After(30 * time.Second):
-		http.Error(w, "Timeout waiting for response", http.StatusRequestTimeout)
 	}
 }
 
+func (t *HTTPTransport) removePending(key string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.pending, key)
+}
+
+// handleGet opens the server-to-client SSE stream for a session, replaying
+// missed events when the client resumes with Last-Event-ID
+func (t *HTTPTransport) handleGet(w http.ResponseWriter, r *http.Request) {
+	if !acceptsEventStream(r) {
+		http.Error(w, "Accept header must include text/event-stream", http.StatusNotAcceptable)
+		return
+	}
+
+	session, status := t.lookupSession(r)
+	if session == nil {
+		http.Error(w, http.StatusText(status), status)
+		return
+	}
+
+	events, replay, err := session.subscribe(r.Header.Get(lastEventIDHeader))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	defer session.unsubscribe(events)
+
+	setSSEHeaders(w)
+	w.WriteHeader(http.StatusOK)
+	if flusher, ok := w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+
+	for _, event := range replay {
+		if err := writeSSEEvent(w, event); err != nil {
+			return
+		}
+	}
+
+	keepAlive := time.NewTicker(keepAliveInterval)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			if err := writeSSEEvent(w, event); err != nil {
+				return
+			}
+		case <-keepAlive.C:
+			if _, err := io.WriteString(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+			session.touch()
+			if flusher, ok := w.(http.Flusher); ok {
+				flusher.Flush()
+			}
+		case <-session.done:
+			return
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+// handleDelete terminates a session at the client's request
+func (t *HTTPTransport) handleDelete(w http.ResponseWriter, r *http.Request) {
+	session, status := t.lookupSession(r)
+	if session == nil {
+		http.Error(w, http.StatusText(status), status)
+		return
+	}
+
+	t.mu.Lock()
+	delete(t.sessions, session.id)
+	t.mu.Unlock()
+	session.close()
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (t *HTTPTransport) createSession() (*httpSession, error) {
+	session, err := newHTTPSession()
+	if err != nil {
+		return nil, err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.closed {
+		return nil, fmt.Errorf("transport is closed")
+	}
+	t.evictSessionsLocked()
+	t.sessions[session.id] = session
+	return session, nil
+}
+
+// expireSessions ends idle sessions every half idle timeout until the
+// transport is closed, so that sessions of clients that went away without a
+// DELETE request do not linger when no new sessions are created
+func (t *HTTPTransport) expireSessions() {
+	defer t.wg.Done()
+
+	ticker := time.NewTicker(t.config.SessionIdleTimeout / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-t.done:
+			return
+		case <-ticker.C:
+			t.mu.Lock()
+			if !t.closed {
+				t.expireSessionsLocked()
+			}
+			t.mu.Unlock()
+		}
+	}
+}
+
+// expireSessionsLocked ends the sessions that have been idle for longer than
+// the idle timeout
+func (t *HTTPTransport) expireSessionsLocked() {
+	now := time.Now()
+	for id, session := range t.sessions {
+		lastSeen, streaming := session.idleSince()
+		if !streaming && now.Sub(lastSeen) > t.config.SessionIdleTimeout {
+			delete(t.sessions, id)
+			session.close()
+		}
+	}
+}
+
+// evictSessionsLocked ends the idle sessions, then the least recently used
+// one if there is still no room for a new session
+func (t *HTTPTransport) evictSessionsLocked() {
+	t.expireSessionsLocked()
+	if len(t.sessions) < t.config.MaxSessions {
+		return
+	}
+
+	var oldest *httpSession
+	var oldestSeen time.Time
+	for _, session := range t.sessions {
+		lastSeen, _ := session.idleSince()
+		if oldest == nil || lastSeen.Before(oldestSeen) {
+			oldest, oldestSeen = session, lastSeen
+		}
+	}
+	if oldest != nil {
+		delete(t.sessions, oldest.id)
+		oldest.close()
+	}
+}
+
+// lookupSession finds the session named by the Mcp-Session-Id header. It
+// returns the HTTP status to reply with when the session is missing.
+func (t *HTTPTransport) lookupSession(r *http.Request) (*httpSession, int) {
+	id := r.Header.Get(sessionIDHeader)
+	if id == "" {
+		return nil, http.StatusBadRequest
+	}
+
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	session, ok := t.sessions[id]
+	if !ok {
+		return nil, http.StatusNotFound
+	}
+	session.touch()
+	return session, http.StatusOK
+}
+
 // handleCORS handles CORS preflight requests
 func (t *HTTPTransport) handleCORS(w http.ResponseWriter, r *http.Request) {
 	if r.Method == "OPTIONS" {
-		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+		setCORSHeaders(w)
 		w.WriteHeader(http.StatusOK)
 		return
 	}
 	http.NotFound(w, r)
-}
\ No newline at end of file
+}
+
+func setCORSHeaders(w http.ResponseWriter) {
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Access-Control-Allow-Methods", "GET, POST, DELETE, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Accept, Mcp-Session-Id, MCP-Protocol-Version, Last-Event-ID")
+	w.Header().Set("Access-Control-Expose-Headers", "Mcp-Session-Id")
+}
+
+func setSSEHeaders(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+}
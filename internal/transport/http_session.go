@@ -0,0 +1,213 @@
+package transport
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// standaloneStream is the stream ID of the server-to-client GET stream
+	standaloneStream = 0
+	// maxSessionHistory bounds the number of events kept for Last-Event-ID resumption
+	maxSessionHistory = 256
+	// listenerBuffer is the number of events queued for a GET stream before new events are only kept in history
+	listenerBuffer = 64
+)
+
+// sseEvent is a single Server-Sent Event delivered on one of a session's streams
+type sseEvent struct {
+	stream int
+	seq    int
+	data   []byte
+}
+
+// ID returns the event ID sent to the client, which encodes the stream so
+// that Last-Event-ID identifies where to resume.
+func (e sseEvent) ID() string {
+	return fmt.Sprintf("%d-%d", e.stream, e.seq)
+}
+
+// parseEventID splits a Last-Event-ID value into its stream and sequence number
+func parseEventID(id string) (int, int, error) {
+	streamPart, seqPart, ok := strings.Cut(id, "-")
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid event ID %q", id)
+	}
+	stream, err := strconv.Atoi(streamPart)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid event ID %q", id)
+	}
+	seq, err := strconv.Atoi(seqPart)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid event ID %q", id)
+	}
+	return stream, seq, nil
+}
+
+// httpSession holds the state of a single Streamable HTTP client session
+type httpSession struct {
+	id         string
+	mu         sync.Mutex
+	nextStream int
+	nextSeq    int
+	history    []sseEvent
+	listener   chan sseEvent
+	done       chan struct{}
+	closed     bool
+	lastSeen   time.Time // time of the last request or GET stream activity
+}
+
+func newHTTPSession() (*httpSession, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, fmt.Errorf("error generating session ID: %w", err)
+	}
+
+	return &httpSession{
+		id:         hex.EncodeToString(buf),
+		nextStream: standaloneStream + 1,
+		done:       make(chan struct{}),
+		lastSeen:   time.Now(),
+	}, nil
+}
+
+// touch records activity on the session
+func (s *httpSession) touch() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastSeen = time.Now()
+}
+
+// idleSince returns when the session was last active, and whether it has an
+// open GET stream
+func (s *httpSession) idleSince() (time.Time, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lastSeen, s.listener != nil
+}
+
+// openStream allocates a new stream ID for an SSE response to a POST
+func (s *httpSession) openStream() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stream := s.nextStream
+	s.nextStream++
+	return stream
+}
+
+// record stores an event in the session history so it can be replayed
+func (s *httpSession) record(stream int, data []byte) sseEvent {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.recordLocked(stream, data)
+}
+
+func (s *httpSession) recordLocked(stream int, data []byte) sseEvent {
+	s.nextSeq++
+	event := sseEvent{stream: stream, seq: s.nextSeq, data: data}
+	s.history = append(s.history, event)
+	if len(s.history) > maxSessionHistory {
+		s.history = s.history[len(s.history)-maxSessionHistory:]
+	}
+	return event
+}
+
+// publish sends a message on the standalone GET stream. Messages sent while
+// no client is listening stay in the history for resumption.
+func (s *httpSession) publish(data []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	event := s.recordLocked(standaloneStream, data)
+	if s.listener == nil {
+		return
+	}
+	select {
+	case s.listener <- event:
+	default:
+	}
+}
+
+// subscribe attaches a GET stream to the session, replacing any existing one
+// so that each message is delivered on exactly one stream. Events after
+// lastEventID on the stream it identifies are returned for replay.
+func (s *httpSession) subscribe(lastEventID string) (chan sseEvent, []sseEvent, error) {
+	replayStream, replaySeq := standaloneStream, -1
+	if lastEventID != "" {
+		stream, seq, err := parseEventID(lastEventID)
+		if err != nil {
+			return nil, nil, err
+		}
+		replayStream, replaySeq = stream, seq
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.listener != nil {
+		close(s.listener)
+	}
+	s.listener = make(chan sseEvent, listenerBuffer)
+
+	var replay []sseEvent
+	if replaySeq >= 0 {
+		for _, event := range s.history {
+			if event.stream == replayStream && event.seq > replaySeq {
+				replay = append(replay, event)
+			}
+		}
+	}
+
+	return s.listener, replay, nil
+}
+
+// unsubscribe detaches a GET stream if it is still the active one
+func (s *httpSession) unsubscribe(ch chan sseEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.listener == ch {
+		close(s.listener)
+		s.listener = nil
+	}
+}
+
+// close ends the session and disconnects its GET stream
+func (s *httpSession) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+	s.closed = true
+	close(s.done)
+}
+
+// writeSSEEvent writes a single event to an SSE response and flushes it
+func writeSSEEvent(w http.ResponseWriter, event sseEvent) error {
+	if _, err := fmt.Fprintf(w, "id: %s\nevent: message\ndata: %s\n\n", event.ID(), event.data); err != nil {
+		return err
+	}
+	if flusher, ok := w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+	return nil
+}
+
+// acceptsEventStream reports whether the request accepts an SSE response
+func acceptsEventStream(r *http.Request) bool {
+	for _, accept := range r.Header.Values("Accept") {
+		for _, mediaType := range strings.Split(accept, ",") {
+			mediaType, _, _ = strings.Cut(mediaType, ";")
+			if strings.TrimSpace(mediaType) == "text/event-stream" {
+				return true
+			}
+		}
+	}
+	return false
+}
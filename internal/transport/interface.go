@@ -1,6 +1,8 @@
 package transport
 
 import (
+	"time"
+
 	"mcp-bridge/pkg/types"
 )
 
@@ -36,8 +38,21 @@ type HTTPConfig struct {
 	Host string `json:"host"`
 	Port int    `json:"port"`
 	CORS bool   `json:"cors"`
+	// SessionIdleTimeout ends sessions without requests or an open GET
+	// stream for this long; zero uses DefaultSessionIdleTimeout
+	SessionIdleTimeout time.Duration `json:"-"`
+	// MaxSessions caps the number of sessions; zero uses DefaultMaxSessions
+	MaxSessions int `json:"-"`
 }
 
+// Session limits of the HTTP transport. Sessions of clients that disconnect
+// without a DELETE request are only ended by these limits; idle sessions are
+// checked every half SessionIdleTimeout.
+const (
+	DefaultSessionIdleTimeout = 30 * time.Minute
+	DefaultMaxSessions        = 1000
+)
+
 func (c *HTTPConfig) GetType() string {
 	return "http"
 }
\ No newline at end of file
@@ -1,5 +1,22 @@
 package types
 
+// LatestProtocolVersion is the MCP protocol revision offered to clients that
+// request an unsupported version.
+const LatestProtocolVersion = "2025-06-18"
+
+// SupportedProtocolVersions lists the MCP protocol revisions this server can speak.
+var SupportedProtocolVersions = []string{LatestProtocolVersion, "2025-03-26", "2024-11-05"}
+
+// IsSupportedProtocolVersion reports whether version is in SupportedProtocolVersions.
+func IsSupportedProtocolVersion(version string) bool {
+	for _, v := range SupportedProtocolVersions {
+		if v == version {
+			return true
+		}
+	}
+	return false
+}
+
 type JSONRPCMessage struct {
 	JSONRpc string        `json:"jsonrpc"`
 	ID      interface{}   `json:"id,omitempty"`
@@ -71,9 +88,22 @@ type ServerInfo struct {
 }
 
 type Tool struct {
-	Name        string      `json:"name"`
-	Description string      `json:"description"`
-	InputSchema interface{} `json:"inputSchema"`
+	Name         string           `json:"name"`
+	Description  string           `json:"description"`
+	InputSchema  interface{}      `json:"inputSchema"`
+	OutputSchema interface{}      `json:"outputSchema,omitempty"`
+	Annotations  *ToolAnnotations `json:"annotations,omitempty"`
+}
+
+// ToolAnnotations describe how a tool behaves so that clients can, for
+// example, ask for confirmation before a destructive call. They are hints
+// and must not be relied on for security.
+type ToolAnnotations struct {
+	Title           string `json:"title,omitempty"`
+	ReadOnlyHint    *bool  `json:"readOnlyHint,omitempty"`
+	DestructiveHint *bool  `json:"destructiveHint,omitempty"`
+	IdempotentHint  *bool  `json:"idempotentHint,omitempty"`
+	OpenWorldHint   *bool  `json:"openWorldHint,omitempty"`
 }
 
 type ToolsListResult struct {
@@ -87,7 +117,12 @@ type CallToolParams struct {
 
 type CallToolResult struct {
 	Content []ToolResult `json:"content"`
-	IsError bool         `json:"isError,omitempty"`
+	// StructuredContent is the result as a JSON object conforming to the
+	// tool's output schema, if any
+	StructuredContent map[string]interface{} `json:"structuredContent,omitempty"`
+	IsError           bool                   `json:"isError,omitempty"`
+	// Meta carries metadata about the call, such as the number of attempts
+	Meta map[string]interface{} `json:"_meta,omitempty"`
 }
 
 type ToolResult struct {
@@ -171,6 +206,11 @@ type LoggingParams struct {
 	Logger string       `json:"logger,omitempty"`
 }
 
+type CancelledParams struct {
+	RequestID interface{} `json:"requestId"`
+	Reason    string      `json:"reason,omitempty"`
+}
+
 type NotificationParams struct {
 	Method string      `json:"method"`
 	Params interface{} `json:"params,omitempty"`